@@ -1,70 +1,386 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/Chirag314/march-mania-2026-go/internal/mm"
 )
 
 func main() {
-	var dataDir, outDir string
-	flag.StringVar(&dataDir, "data_dir", "data", "directory with Kaggle CSV files")
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run does the work of main, so the loader is closed by its deferred Close
+// before main exits on an error.
+func run() error {
+	var dataDir, outDir, leagueList, excludeGames, eloConfigPath string
+	var lenient, useCache, writeManifest, strict, trainSecondary, eloHistory, btBaseline bool
+	var cutoffDay int
+	flag.StringVar(&dataDir, "data_dir", "data", "directory or .zip archive with Kaggle CSV files")
 	flag.StringVar(&outDir, "out_dir", "artifacts", "output directory")
+	flag.StringVar(&leagueList, "leagues", "M,W", "comma separated leagues to load (M, W)")
+	flag.BoolVar(&lenient, "lenient", false, "skip malformed rows instead of failing")
+	flag.BoolVar(&useCache, "cache", true, "cache parsed tables under <out_dir>/cache")
+	flag.BoolVar(&writeManifest, "manifest", true, "record the data manifest in <out_dir>/data_manifest.json")
+	flag.BoolVar(&strict, "strict", false, "fail when validation reports errors")
+	flag.StringVar(&excludeGames, "exclude_games", "", "comma separated game kinds to drop from the regular season (conf, nonconf, conftourney)")
+	flag.BoolVar(&trainSecondary, "train_secondary", false, "add secondary tournament games (NIT, CBI, ...) to the training matchups; their teams are unseeded, so DSeed is missing for them")
+	flag.IntVar(&cutoffDay, "cutoff_day", 0, "build season features from games and rankings up to this DayNum (0 uses the whole regular season)")
+	flag.BoolVar(&eloHistory, "elo_history", false, "write the day-by-day Elo ratings to <out_dir>/elo_history.csv")
+	glickoCfg := mm.DefaultGlickoConfig()
+	flag.Float64Var(&glickoCfg.Tau, "glicko_tau", glickoCfg.Tau, "Glicko-2 volatility constraint")
+	flag.Float64Var(&glickoCfg.HomeAdv, "glicko_home", glickoCfg.HomeAdv, "Glicko-2 home-court advantage in rating points")
+	masseyCfg := mm.DefaultMasseyConfig()
+	flag.BoolVar(&masseyCfg.WinLoss, "massey_winloss", masseyCfg.WinLoss, "fit Massey ratings to wins instead of point margins")
+	flag.IntVar(&masseyCfg.MarginCap, "massey_cap", masseyCfg.MarginCap, "cap on a game's margin in the Massey fit (0 disables)")
+	flag.BoolVar(&masseyCfg.FitHome, "massey_home", masseyCfg.FitHome, "estimate a home-court term in the Massey fit")
+	lrmcCfg := mm.DefaultLRMCConfig()
+	flag.Float64Var(&lrmcCfg.Scale, "lrmc_scale", lrmcCfg.Scale, "LRMC margin scale in points")
+	flag.Float64Var(&lrmcCfg.HomeAdv, "lrmc_home", lrmcCfg.HomeAdv, "LRMC home-court advantage in points")
+	btCfg := mm.DefaultBTConfig()
+	flag.Float64Var(&btCfg.Ridge, "bt_ridge", btCfg.Ridge, "ridge penalty on Bradley-Terry strengths")
+	flag.BoolVar(&btCfg.FitHome, "bt_home", btCfg.FitHome, "estimate a home-court term in the Bradley-Terry fit")
+	flag.BoolVar(&btBaseline, "bt_baseline", false, "write Bradley-Terry predictions for the sample submission to <out_dir>/submission_bt.csv")
+	effCfg := mm.DefaultEfficiencyConfig()
+	flag.Float64Var(&effCfg.HomeEdge, "eff_home", effCfg.HomeEdge, "fractional home-court edge removed from adjusted efficiencies")
+	ridgeCfg := mm.DefaultRidgeConfig()
+	flag.Float64Var(&ridgeCfg.Lambda, "ridge_lambda", ridgeCfg.Lambda, "prior precision of ridge offense/defense ratings")
+	flag.BoolVar(&ridgeCfg.FitHome, "ridge_home", ridgeCfg.FitHome, "estimate a home-court term in the ridge ratings")
+	flag.StringVar(&eloConfigPath, "elo_config", "", "JSON Elo config (e.g. from tune_elo); elo_* flags given on the command line override it")
+	sosCfg := mm.DefaultSOSConfig()
+	flag.IntVar(&sosCfg.TopN, "sos_top_n", sosCfg.TopN, "Elo rank that counts as a top opponent in schedule features")
+	formCfg := mm.DefaultFormConfig()
+	flag.IntVar(&formCfg.LastGames, "form_games", formCfg.LastGames, "recent-form window in games (0 disables)")
+	flag.IntVar(&formCfg.LastDays, "form_days", formCfg.LastDays, "recent-form window in days before Selection Sunday or the cutoff day (0 disables)")
+	flag.Float64Var(&formCfg.HalfLife, "form_half_life", formCfg.HalfLife, "half-life in days of the decayed margin and efficiency (0 disables)")
+	eloCfg := mm.DefaultEloConfig()
+	flag.Float64Var(&eloCfg.K, "elo_k", eloCfg.K, "Elo K factor")
+	flag.Float64Var(&eloCfg.HomeAdv, "elo_home", eloCfg.HomeAdv, "Elo home-court advantage in rating points")
+	flag.Float64Var(&eloCfg.MOVExponent, "elo_mov", eloCfg.MOVExponent, "Elo margin-of-victory exponent (0 disables)")
+	flag.Float64Var(&eloCfg.OTDamp, "elo_ot_damp", eloCfg.OTDamp, "fraction by which overtime games' Elo updates are shrunk")
+	flag.BoolVar(&eloCfg.Carryover, "elo_carry", eloCfg.Carryover, "carry Elo ratings between seasons")
+	flag.Float64Var(&eloCfg.Regress, "elo_regress", eloCfg.Regress, "fraction of a carried Elo rating regressed to the mean")
+	flag.BoolVar(&eloCfg.RegressToConf, "elo_regress_conf", eloCfg.RegressToConf, "regress carried Elo ratings to the conference mean")
+	flag.Float64Var(&eloCfg.NewTeam, "elo_new_team", eloCfg.NewTeam, "first Elo rating of new programs when carrying over")
 	flag.Parse()
 
+	if eloConfigPath != "" {
+		// The elo_* flags write into eloCfg, so reapply the ones given on
+		// the command line on top of the loaded file.
+		set := map[string]string{}
+		flag.Visit(func(f *flag.Flag) {
+			if strings.HasPrefix(f.Name, "elo_") {
+				set[f.Name] = f.Value.String()
+			}
+		})
+		loaded, err := mm.LoadEloConfigJSON(eloConfigPath)
+		if err != nil {
+			return err
+		}
+		eloCfg = loaded
+		for name, v := range set {
+			if err := flag.Set(name, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	leagues, err := mm.ParseLeagues(leagueList)
+	if err != nil {
+		return err
+	}
+	exclude, err := mm.ParseGameKinds(excludeGames)
+	if err != nil {
+		return err
+	}
+	for _, k := range exclude {
+		if k == mm.GameSecondary {
+			// Secondary tournaments never enter the rating input, so there is
+			// nothing to exclude; they are opt-in via --train_secondary.
+			return fmt.Errorf("--exclude_games: %q games are not part of the regular season (see --train_secondary)", k)
+		}
+	}
+
+	loader := mm.NewLoader(dataDir)
+	defer loader.Close()
+	loader.Lenient = lenient
+	if useCache {
+		loader.CacheDir = filepath.Join(outDir, "cache")
+	}
+
 	fmt.Println("Reading regular season...")
-	reg, err := mm.ReadRegularSeasonCompact(dataDir)
-	must(err)
+	reg, err := loader.RegularSeasonCompact(leagues...)
+	if err != nil {
+		return err
+	}
+	printLeagueCounts(reg, func(r mm.RegularSeasonCompactRow) mm.League { return r.League })
 
 	fmt.Println("Reading tourney results...")
-	tour, err := mm.ReadTourneyCompact(dataDir)
-	must(err)
+	tour, err := loader.TourneyCompact(leagues...)
+	if err != nil {
+		return err
+	}
+	printLeagueCounts(tour, func(r mm.TourneyCompactRow) mm.League { return r.League })
 
 	fmt.Println("Reading seeds...")
-	seeds, err := mm.ReadSeeds(dataDir)
-	must(err)
+	seeds, err := loader.Seeds(leagues...)
+	if err != nil {
+		return err
+	}
+	printLeagueCounts(seeds, func(r mm.SeedRow) mm.League { return r.League })
 
 	fmt.Println("Reading Massey (optional)...")
-	massey, _ := mm.ReadMassey(dataDir)
+	massey, err := loader.Massey(leagues...)
+	switch {
+	case errors.Is(err, mm.ErrNotPresent):
+		fmt.Println("  no Massey ordinals file, skipping")
+	case err != nil:
+		return err
+	default:
+		fmt.Println(" ", mm.SummarizeMassey(massey))
+	}
+
+	fmt.Println("Reading detailed results (optional)...")
+	detailed, err := loader.RegularSeasonDetailed(leagues...)
+	switch {
+	case errors.Is(err, mm.ErrNotPresent):
+		fmt.Println("  no detailed results, skipping adjusted efficiencies")
+	case err != nil:
+		return err
+	default:
+		printLeagueCounts(detailed, func(r mm.DetailedResultRow) mm.League { return r.League })
+	}
+
+	fmt.Println("Reading team metadata (optional)...")
+	meta, err := mm.LoadTeamMeta(loader, leagues...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Reading conference and secondary tournaments (optional)...")
+	confTourney, err := loader.ConferenceTourneyGames(leagues...)
+	if err != nil && !errors.Is(err, mm.ErrNotPresent) {
+		return err
+	}
+	secondary, err := loader.SecondaryTourneyCompact(leagues...)
+	if err != nil && !errors.Is(err, mm.ErrNotPresent) {
+		return err
+	}
+
+	mm.TagGames(reg, meta, confTourney)
+	kinds := mm.CountGameKinds(reg)
+	fmt.Printf("  regular season: %d conf, %d nonconf, %d conftourney, %d untagged; %d secondary tourney games\n",
+		kinds[mm.GameConference], kinds[mm.GameNonConference], kinds[mm.GameConfTourney], kinds[mm.GameUntagged], len(secondary))
+
+	fmt.Println("Reading sample submission IDs...")
+	ids, err := loader.SampleSubmission()
+	if err != nil {
+		return err
+	}
+
+	printDropped(loader)
+
+	// Validate the source tables as loaded, before --exclude_games, any rating
+	// fit or any output, so a strict run fails fast and leaves the output
+	// directory alone.
+	report := mm.Validate(mm.ValidationInput{
+		Regular:       reg,
+		Tourney:       tour,
+		Seeds:         seeds,
+		Massey:        massey,
+		SubmissionIDs: ids,
+	})
+	report.Write(os.Stdout, 5)
+	if strict && report.HasErrors() {
+		return fmt.Errorf("validation failed with %d errors (strict mode)", report.Count(mm.SeverityError))
+	}
+	if loader.CacheHits > 0 {
+		fmt.Printf("Loaded %d tables from cache %s\n", loader.CacheHits, loader.CacheDir)
+	}
+
+	if len(exclude) > 0 {
+		detailed = mm.FilterDetailed(detailed, reg, exclude...)
+		reg = mm.FilterGames(reg, exclude...)
+		fmt.Printf("Excluding %v: %d regular season games, %d detailed results left\n", exclude, len(reg), len(detailed))
+	}
+
+	if writeManifest {
+		fmt.Println("Recording data manifest...")
+		manifest, err := mm.BuildManifest(loader)
+		if err != nil {
+			return err
+		}
+		if err := mm.SaveManifestJSON(filepath.Join(outDir, "data_manifest.json"), manifest); err != nil {
+			return err
+		}
+	}
 
 	fmt.Println("Building Elo...")
-	eloEnd := mm.BuildEloEnd(reg, mm.DefaultEloConfig())
+	elo := mm.BuildEloHistory(reg, eloCfg, meta)
+	eloEnd := elo.End()
+	if eloHistory {
+		if err := mm.WriteRatingHistoryCSV(filepath.Join(outDir, "elo_history.csv"), elo); err != nil {
+			return err
+		}
+	}
+
+	// A cutoff applies within each season; earlier seasons still carry their
+	// full Elo history into the next.
+	seasonGames, seasonMassey := reg, massey
+	if cutoffDay > 0 {
+		fmt.Printf("  using games and rankings through day %d\n", cutoffDay)
+		eloEnd = elo.AsOf(cutoffDay)
+		seasonGames = mm.GamesThrough(reg, cutoffDay)
+		seasonMassey = mm.MasseyThrough(massey, cutoffDay)
+		detailed = mm.DetailedThrough(detailed, cutoffDay)
+	}
+
+	fmt.Println("Building Glicko-2...")
+	glicko := mm.BuildGlicko(seasonGames, glickoCfg)
+
+	fmt.Println("Fitting Massey ratings...")
+	masseyRatings, err := mm.BuildMasseyRatings(seasonGames, masseyCfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Building Colley and LRMC ratings...")
+	colley, err := mm.BuildColley(seasonGames)
+	if err != nil {
+		return err
+	}
+	lrmc := mm.BuildLRMC(seasonGames, lrmcCfg)
+
+	fmt.Println("Fitting Bradley-Terry strengths...")
+	bt, err := mm.FitBradleyTerry(seasonGames, btCfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Adjusting tempo-free efficiencies...")
+	eff := mm.BuildEfficiency(detailed, effCfg)
+
+	fmt.Println("Fitting ridge offense/defense ratings...")
+	ridge, err := mm.BuildRidgeRatings(seasonGames, ridgeCfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Measuring strength of schedule...")
+	sos := mm.BuildSOS(seasonGames, eloEnd, sosCfg)
+
+	fmt.Println("Measuring recent form...")
+	if cutoffDay > 0 {
+		formCfg.EndDay = cutoffDay
+	}
+	form := mm.BuildForm(seasonGames, detailed, elo, formCfg)
 
 	fmt.Println("Aggregating team-season features...")
-	agg := mm.BuildTeamSeasonAgg(reg, seeds, massey)
+	agg := mm.BuildTeamSeasonAgg(seasonGames, seeds, seasonMassey)
 	mm.AttachEloEnd(agg, eloEnd)
+	mm.AttachGlicko(agg, glicko)
+	mm.AttachMasseyRatings(agg, masseyRatings)
+	mm.AttachColley(agg, colley)
+	mm.AttachLRMC(agg, lrmc)
+	mm.AttachBradleyTerry(agg, bt)
+	mm.AttachEfficiency(agg, eff)
+	mm.AttachRidgeRatings(agg, ridge)
+	mm.AttachSOS(agg, sos)
+	mm.AttachForm(agg, form)
+	mm.AttachTeamMeta(agg, meta)
 
 	_, err = mm.WriteTeamSeasonAggCSV(outDir, agg)
-	must(err)
+	if err != nil {
+		return err
+	}
 
 	fmt.Println("Building train matchups from tourney...")
-	train := mm.BuildTrainMatchupsFromTourney(tour)
+	trainGames := append([]mm.TourneyCompactRow{}, tour...)
+	if trainSecondary {
+		for _, g := range secondary {
+			trainGames = append(trainGames, g.Tourney())
+		}
+	}
+	train := mm.BuildTrainMatchupsFromTourney(trainGames)
 	train = mm.JoinFeatures(train, agg)
 
-	fmt.Println("Reading sample submission IDs...")
-	ids, err := mm.ReadSampleSubmission(dataDir)
-	must(err)
-
 	fmt.Println("Building test matchups from sample submission...")
 	test, err := mm.BuildTestMatchupsFromIDs(ids)
-	must(err)
+	if err != nil {
+		return err
+	}
 	test = mm.JoinFeatures(test, agg)
 
-	_, err = mm.WriteMatchupsCSV(outDir, "features_train.csv", train)
-	must(err)
-	_, err = mm.WriteMatchupsCSV(outDir, "features_test.csv", test)
-	must(err)
+	features := mm.MatchupFeatureNames()
+	_, err = mm.WriteMatchupsCSV(outDir, "features_train.csv", features, train)
+	if err != nil {
+		return err
+	}
+	_, err = mm.WriteMatchupsCSV(outDir, "features_test.csv", features, test)
+	if err != nil {
+		return err
+	}
+	if btBaseline {
+		if err := writeBTBaseline(filepath.Join(outDir, "submission_bt.csv"), bt, ids); err != nil {
+			return err
+		}
+	}
 
 	fmt.Printf("Done.\n- %s\n- %s\n",
 		filepath.Join(outDir, "features_train.csv"),
 		filepath.Join(outDir, "features_test.csv"),
 	)
+	return nil
 }
 
-func must(err error) {
+func printLeagueCounts[T any](rows []T, league func(T) mm.League) {
+	counts := map[mm.League]int{}
+	for _, r := range rows {
+		counts[league(r)]++
+	}
+	for _, lg := range mm.AllLeagues {
+		if n, ok := counts[lg]; ok {
+			fmt.Printf("  %s: %d rows\n", lg, n)
+		}
+	}
+}
+
+func printDropped(loader *mm.Loader) {
+	if len(loader.Dropped) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(loader.Dropped))
+	for p := range loader.Dropped {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	fmt.Printf("Dropped %d malformed rows:\n", loader.TotalDropped())
+	for _, p := range paths {
+		fmt.Printf("  %s: %d\n", p, loader.Dropped[p])
+	}
+}
+
+func writeBTBaseline(path string, bt *mm.BTModel, ids []string) error {
+	w, err := mm.NewCSVWriter(path, []string{"ID", "Pred"})
 	if err != nil {
-		panic(err)
+		return err
+	}
+	for _, id := range ids {
+		p, err := bt.PredictID(id)
+		if err != nil {
+			_ = w.Close()
+			return err
+		}
+		w.WriteRow([]string{id, fmt.Sprintf("%.6f", p)})
 	}
+	return w.Close()
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Chirag314/march-mania-2026-go/internal/mm"
+)
+
+func main() {
+	var cacheDir string
+	var verbose bool
+	flag.StringVar(&cacheDir, "cache_dir", "artifacts/cache", "table cache directory")
+	flag.BoolVar(&verbose, "v", false, "list the columns of every snapshot")
+	flag.Parse()
+
+	entries, err := mm.ListCache(cacheDir)
+	must(err)
+	if len(entries) == 0 {
+		fmt.Println("No cached tables in", cacheDir)
+		return
+	}
+
+	for _, h := range entries {
+		fmt.Printf("%s (%s)\n", h.Source, h.RowType)
+		fmt.Printf("  rows=%d dropped=%d lenient=%t version=%d parser=%d\n", h.Rows, h.Dropped, h.Lenient, h.Version, h.Parser)
+		fmt.Printf("  source=%s size=%d mtime=%s\n", h.SourcePath, h.Size, h.ModTime.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  sha256=%s created=%s\n", h.SHA256, h.Created.Format("2006-01-02 15:04:05"))
+		if verbose {
+			for _, c := range h.Columns {
+				fmt.Printf("    %-12s %s\n", c.Name, c.Kind)
+			}
+		}
+	}
+}
+
+func must(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Chirag314/march-mania-2026-go/internal/mm"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run does the work of main, so the loader is closed by its deferred Close
+// before main exits on an error.
+func run() error {
+	var dataDir, writePath, comparePath string
+	flag.StringVar(&dataDir, "data_dir", "data", "directory or .zip archive with Kaggle CSV files")
+	flag.StringVar(&writePath, "write", "", "write the manifest of data_dir to this JSON file")
+	flag.StringVar(&comparePath, "compare", "", "compare data_dir against a saved manifest JSON file")
+	flag.Parse()
+
+	loader := mm.NewLoader(dataDir)
+	defer loader.Close()
+
+	fmt.Println("Scanning", dataDir)
+	m, err := mm.BuildManifest(loader)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range m.Files {
+		fmt.Printf("%-45s %10d bytes %9d rows", f.Name, f.Size, f.Rows)
+		if f.MaxSeason > 0 {
+			fmt.Printf("  seasons %d-%d", f.MinSeason, f.MaxSeason)
+		}
+		if f.MaxDayNum > 0 {
+			fmt.Printf("  days %d-%d", f.MinDayNum, f.MaxDayNum)
+		}
+		fmt.Printf("  %s\n", f.SHA256[:12])
+	}
+
+	if writePath != "" {
+		if err := mm.SaveManifestJSON(writePath, m); err != nil {
+			return err
+		}
+		fmt.Println("Wrote:", writePath)
+	}
+
+	if comparePath != "" {
+		want, err := mm.LoadManifestJSON(comparePath)
+		if err != nil {
+			return err
+		}
+		diffs := mm.CompareManifests(want, m)
+		if len(diffs) == 0 {
+			fmt.Printf("OK: %s matches %s (%d files)\n", dataDir, comparePath, len(m.Files))
+			return nil
+		}
+		fmt.Printf("%d differences against %s:\n", len(diffs), comparePath)
+		for _, d := range diffs {
+			fmt.Println(" ", d)
+		}
+		return fmt.Errorf("%s does not match %s", dataDir, comparePath)
+	}
+	return nil
+}
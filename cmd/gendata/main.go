@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Chirag314/march-mania-2026-go/internal/mm"
+	"github.com/Chirag314/march-mania-2026-go/internal/synth"
+)
+
+func main() {
+	cfg := synth.DefaultConfig()
+	var outDir, leagueList string
+
+	flag.StringVar(&outDir, "out_dir", "data/synth", "directory to write the synthetic Kaggle files to")
+	flag.Int64Var(&cfg.Seed, "seed", cfg.Seed, "random seed")
+	flag.IntVar(&cfg.FirstSeason, "first_season", cfg.FirstSeason, "first season to generate")
+	flag.IntVar(&cfg.LastSeason, "last_season", cfg.LastSeason, "last season to generate")
+	flag.IntVar(&cfg.TeamsPerLeague, "teams", cfg.TeamsPerLeague, "teams per league")
+	flag.IntVar(&cfg.Conferences, "conferences", cfg.Conferences, "conferences per league")
+	flag.IntVar(&cfg.SeedsPerRegion, "seeds_per_region", cfg.SeedsPerRegion, "seeds per bracket region (power of two, max 16)")
+	flag.StringVar(&leagueList, "leagues", "M,W", "comma separated leagues to generate (M, W)")
+	flag.Parse()
+
+	leagues, err := mm.ParseLeagues(leagueList)
+	must(err)
+	cfg.Leagues = leagues
+
+	must(synth.Generate(outDir, cfg))
+	fmt.Printf("Wrote synthetic data for seasons %d-%d to %s\n", cfg.FirstSeason, cfg.LastSeason, outDir)
+}
+
+func must(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
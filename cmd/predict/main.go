@@ -27,16 +27,17 @@ func main() {
 	testPath := filepath.Join(artDir, "features_test.csv")
 	fmt.Println("testPath:", testPath)
 
-	rows, err := mm.ReadMatchupsCSV(testPath)
+	rows, columns, err := mm.ReadMatchupsCSV(testPath)
 	must(err)
 	fmt.Println("rows read:", len(rows))
 	if len(rows) == 0 {
-		panic("no rows read from features_test.csv (check ReadMatchupsCSV and path)")
+		must(fmt.Errorf("no rows read from %s", testPath))
 	}
+	cols, err := mm.FeatureColumns(columns, model.FeatureNames)
+	must(err)
+	X := mm.SelectFeatures(rows, cols)
 
-	if err := os.MkdirAll(outDir, 0o755); err != nil {
-		panic(err)
-	}
+	must(os.MkdirAll(outDir, 0o755))
 
 	outPath := filepath.Join(outDir, "submission.csv")
 	f, err := os.Create(outPath)
@@ -48,11 +49,8 @@ func main() {
 
 	must(w.Write([]string{"ID", "Pred"}))
 
-	for _, r := range rows {
-		x := []float64{
-			r.DSeed, r.DElo, r.DWinPct, r.DAvgMargin, r.DAvgPF, r.DAvgPA, r.DMasseyOrd,
-		}
-		p := model.PredictProba(x)
+	for i, r := range rows {
+		p := model.PredictProba(X[i])
 		p = mm.TemperatureScale(p, temp)
 		p = mm.ClipProb(p, 0.02, 0.98)
 
@@ -64,6 +62,7 @@ func main() {
 
 func must(err error) {
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
 }
@@ -3,13 +3,15 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Chirag314/march-mania-2026-go/internal/mm"
 )
 
 func main() {
-	var artDir, outDir, cvMode string
+	var artDir, outDir, cvMode, featureList string
 	var k int
 	var seed int64
 
@@ -21,18 +23,22 @@ func main() {
 	flag.StringVar(&cvMode, "cv", "loso", "cv mode: loso or groupk")
 	var minSeason int
 	flag.IntVar(&minSeason, "min_season", 1985, "minimum season to include in training/CV")
+	flag.StringVar(&featureList, "features", strings.Join(mm.DefaultMatchupFeatureNames(), ","), "comma separated feature columns to train on")
 	flag.Parse()
 
 	trainPath := filepath.Join(artDir, "features_train.csv")
 	fmt.Println("Reading:", trainPath)
 
-	rows, err := mm.ReadMatchupsCSV(trainPath)
+	rows, columns, err := mm.ReadMatchupsCSV(trainPath)
 	must(err)
 	rows = filterMinSeasonLabeled(rows, minSeason)
 	fmt.Println("Train/CV rows after min_season filter:", len(rows))
-	featureNames := []string{"DSeed", "DElo", "DWinPct", "DAvgMargin", "DAvgPF", "DAvgPA", "DMasseyOrd"}
+	featureNames := splitList(featureList)
+	cols, err := mm.FeatureColumns(columns, featureNames)
+	must(err)
+	fmt.Printf("Features (%d): %s\n", len(featureNames), strings.Join(featureNames, ", "))
 
-	X, y := toXY(rows)
+	X, y := toXY(rows, cols)
 
 	var folds []mm.Fold
 	if cvMode == "groupk" {
@@ -72,18 +78,27 @@ func main() {
 	fmt.Println("Saved model:", modelPath)
 }
 
-func toXY(rows []mm.MatchupFeatureRow) (X [][]float64, y []float64) {
+func toXY(rows []mm.MatchupFeatureRow, cols []int) (X [][]float64, y []float64) {
 	for _, r := range rows {
 		if !r.HasLabel {
 			continue
 		}
-		x := []float64{r.DSeed, r.DElo, r.DWinPct, r.DAvgMargin, r.DAvgPF, r.DAvgPA, r.DMasseyOrd}
-		X = append(X, x)
+		X = append(X, mm.SelectFeatures([]mm.MatchupFeatureRow{r}, cols)[0])
 		y = append(y, r.Label)
 	}
 	return
 }
 
+func splitList(s string) []string {
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
 func subsetXY(X [][]float64, y []float64, idx []int) ([][]float64, []float64) {
 	outX := make([][]float64, 0, len(idx))
 	outY := make([]float64, 0, len(idx))
@@ -96,7 +111,8 @@ func subsetXY(X [][]float64, y []float64, idx []int) ([][]float64, []float64) {
 
 func must(err error) {
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
 }
 
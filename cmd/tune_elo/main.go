@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Chirag314/march-mania-2026-go/internal/mm"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run does the work of main, so the loader is closed by its deferred Close
+// before main exits on an error.
+func run() error {
+	var dataDir, outPath, leagueList string
+	var kGrid, homeGrid, movGrid, regressGrid string
+	var minSeason, top int
+	var regressConf bool
+
+	flag.StringVar(&dataDir, "data_dir", "data", "directory or .zip archive with Kaggle CSV files")
+	flag.StringVar(&outPath, "out", "artifacts/elo_config.json", "where to write the best Elo config")
+	flag.StringVar(&leagueList, "leagues", "M,W", "comma separated leagues to load (M, W)")
+	flag.StringVar(&kGrid, "k", "10,20,30,40", "comma separated K values")
+	flag.StringVar(&homeGrid, "home", "0,50,75,100", "comma separated home-court advantages")
+	flag.StringVar(&movGrid, "mov", "0,0.8,1", "comma separated margin-of-victory exponents")
+	flag.StringVar(&regressGrid, "regress", "0.25,0.5,1", "comma separated carryover regression fractions (1 disables carryover; empty keeps carryover off)")
+	flag.BoolVar(&regressConf, "regress_conf", false, "regress carried ratings to the conference mean")
+	flag.IntVar(&minSeason, "min_season", 1985, "first tournament season to score")
+	flag.IntVar(&top, "top", 10, "number of best configs to print")
+	flag.Parse()
+
+	leagues, err := mm.ParseLeagues(leagueList)
+	if err != nil {
+		return err
+	}
+	grid := mm.EloGrid{}
+	grid.K, err = parseFloats(kGrid)
+	if err != nil {
+		return err
+	}
+	grid.HomeAdv, err = parseFloats(homeGrid)
+	if err != nil {
+		return err
+	}
+	grid.MOVExponent, err = parseFloats(movGrid)
+	if err != nil {
+		return err
+	}
+	grid.Regress, err = parseFloats(regressGrid)
+	if err != nil {
+		return err
+	}
+
+	loader := mm.NewLoader(dataDir)
+	defer loader.Close()
+
+	reg, err := loader.RegularSeasonCompact(leagues...)
+	if err != nil {
+		return err
+	}
+	tour, err := loader.TourneyCompact(leagues...)
+	if err != nil {
+		return err
+	}
+	meta, err := mm.LoadTeamMeta(loader, leagues...)
+	if err != nil {
+		return err
+	}
+
+	var scored []mm.TourneyCompactRow
+	for _, g := range tour {
+		if g.Season >= minSeason {
+			scored = append(scored, g)
+		}
+	}
+	if len(scored) == 0 {
+		return fmt.Errorf("no tournament games from season %d on", minSeason)
+	}
+
+	base := mm.DefaultEloConfig()
+	base.RegressToConf = regressConf
+	configs := grid.Configs(base)
+	fmt.Printf("Scoring %d Elo configs on %d tournament games (LOSO)...\n", len(configs), len(scored))
+	trials := mm.TuneElo(reg, scored, meta, configs)
+
+	fmt.Printf("%-4s %8s %6s %6s %5s %7s\n", "rank", "Brier", "K", "home", "mov", "regress")
+	for i, t := range trials {
+		if i == top {
+			break
+		}
+		regress := "off"
+		if t.Config.Carryover {
+			regress = strconv.FormatFloat(t.Config.Regress, 'g', -1, 64)
+		}
+		fmt.Printf("%-4d %8.6f %6g %6g %5g %7s\n", i+1, t.Brier, t.Config.K, t.Config.HomeAdv, t.Config.MOVExponent, regress)
+	}
+
+	if err := mm.SaveEloConfigJSON(outPath, trials[0].Config); err != nil {
+		return err
+	}
+	fmt.Println("Saved best config:", filepath.Clean(outPath))
+	return nil
+}
+
+func parseFloats(s string) ([]float64, error) {
+	var out []float64
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad grid value %q: %w", f, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
@@ -0,0 +1,199 @@
+package mm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Seed is a parsed seed code such as "W01" or "X16a".
+type Seed struct {
+	Region string // W, X, Y or Z
+	Number int    // 1-16
+	PlayIn string // "a" or "b" for First Four teams, "" otherwise
+}
+
+// ParseSeed parses a Kaggle seed code: a region letter, a two digit seed and
+// an optional play-in suffix.
+func ParseSeed(code string) (Seed, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 3 {
+		return Seed{}, fmt.Errorf("bad seed %q", code)
+	}
+	s := Seed{Region: code[:1]}
+	switch s.Region {
+	case "W", "X", "Y", "Z":
+	default:
+		return Seed{}, fmt.Errorf("bad seed region in %q", code)
+	}
+	n, err := strconv.Atoi(code[1:3])
+	if err != nil {
+		return Seed{}, fmt.Errorf("bad seed number in %q", code)
+	}
+	s.Number = n
+	switch rest := code[3:]; rest {
+	case "":
+	case "a", "b":
+		s.PlayIn = rest
+	default:
+		return Seed{}, fmt.Errorf("bad seed suffix in %q", code)
+	}
+	return s, nil
+}
+
+func (s Seed) String() string {
+	return fmt.Sprintf("%s%02d%s", s.Region, s.Number, s.PlayIn)
+}
+
+func (s Seed) IsPlayIn() bool { return s.PlayIn != "" }
+
+// SlotCode is the code the bracket slots use for this seed; play-in teams
+// share the slot of their First Four game ("W16").
+func (s Seed) SlotCode() string {
+	return fmt.Sprintf("%s%02d", s.Region, s.Number)
+}
+
+// SlotRound returns the round of a slot name: 1-6 for "R1W1".."R6CH" and 0
+// for First Four slots, which are named after the seed they produce ("W16").
+func SlotRound(slot string) int {
+	if len(slot) >= 2 && slot[0] == 'R' && slot[1] >= '0' && slot[1] <= '9' {
+		return int(slot[1] - '0')
+	}
+	return 0
+}
+
+// Bracket is one season's tournament structure for a league.
+type Bracket struct {
+	Season int
+	League League
+
+	slots map[string]TourneySlotRow
+	seeds map[string]int // full seed code -> TeamID
+	seed  map[int]Seed   // TeamID -> seed
+	// feeds maps a seed or slot code to the slot its winner advances to.
+	feeds map[string]string
+}
+
+// BuildBracket assembles the bracket of season/league from the slot and seed
+// tables, which may hold any number of seasons and leagues.
+func BuildBracket(season int, league League, slots []TourneySlotRow, seeds []SeedRow) (*Bracket, error) {
+	b := &Bracket{
+		Season: season,
+		League: league,
+		slots:  make(map[string]TourneySlotRow),
+		seeds:  make(map[string]int),
+		seed:   make(map[int]Seed),
+		feeds:  make(map[string]string),
+	}
+	for _, s := range slots {
+		if s.Season != season || s.League != league {
+			continue
+		}
+		b.slots[s.Slot] = s
+		b.feeds[s.StrongSeed] = s.Slot
+		b.feeds[s.WeakSeed] = s.Slot
+	}
+	for _, s := range seeds {
+		if s.Season != season || s.League != league {
+			continue
+		}
+		b.seeds[s.Full.String()] = s.TeamID
+		b.seed[s.TeamID] = s.Full
+	}
+	if len(b.slots) == 0 {
+		return nil, fmt.Errorf("no %s slots for season %d", league, season)
+	}
+	return b, nil
+}
+
+// Seed returns the seed of a team in this bracket.
+func (b *Bracket) Seed(teamID int) (Seed, bool) {
+	s, ok := b.seed[teamID]
+	return s, ok
+}
+
+// Path lists the slots a team would play in, from its first game to the final.
+func (b *Bracket) Path(teamID int) []string {
+	s, ok := b.seed[teamID]
+	if !ok {
+		return nil
+	}
+	code := s.String()
+	if _, ok := b.feeds[code]; !ok {
+		code = s.SlotCode()
+	}
+	var path []string
+	for {
+		next, ok := b.feeds[code]
+		if !ok {
+			return path
+		}
+		path = append(path, next)
+		code = next
+	}
+}
+
+// MeetingSlot returns the slot in which two teams would meet, if both are in
+// the bracket.
+func (b *Bracket) MeetingSlot(teamA, teamB int) (string, bool) {
+	pa := b.Path(teamA)
+	onA := make(map[string]bool, len(pa))
+	for _, s := range pa {
+		onA[s] = true
+	}
+	for _, s := range b.Path(teamB) {
+		if onA[s] {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Teams resolves a seed or slot code to the TeamIDs that can fill it.
+func (b *Bracket) Teams(code string) []int {
+	if id, ok := b.seeds[code]; ok {
+		return []int{id}
+	}
+	s, ok := b.slots[code]
+	if !ok {
+		// A First Four slot code ("W16") may also name two play-in seeds.
+		var out []int
+		for _, suffix := range []string{"a", "b"} {
+			if id, ok := b.seeds[code+suffix]; ok {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+	return append(b.Teams(s.StrongSeed), b.Teams(s.WeakSeed)...)
+}
+
+// GameCities finds the city a game was played in.
+type GameCities struct {
+	cities map[int]CityRow
+	games  map[[4]int]int // [season, day, WTeamID, LTeamID] -> CityID
+}
+
+func NewGameCities(cities []CityRow, games []GameCityRow) *GameCities {
+	g := &GameCities{
+		cities: make(map[int]CityRow, len(cities)),
+		games:  make(map[[4]int]int, len(games)),
+	}
+	for _, c := range cities {
+		g.cities[c.CityID] = c
+	}
+	for _, gc := range games {
+		g.games[[4]int{gc.Season, gc.DayNum, gc.WTeamID, gc.LTeamID}] = gc.CityID
+	}
+	return g
+}
+
+// City returns the city of the game identified by season, day and teams.
+func (g *GameCities) City(season, dayNum, wTeamID, lTeamID int) (CityRow, bool) {
+	id, ok := g.games[[4]int{season, dayNum, wTeamID, lTeamID}]
+	if !ok {
+		return CityRow{}, false
+	}
+	c, ok := g.cities[id]
+	return c, ok
+}
@@ -0,0 +1,159 @@
+package mm
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bradley-Terry strengths fitted per season and league by penalized maximum
+// likelihood: P(i beats j) = sigmoid(θi - θj + h·home), where home is +1 when
+// i is at home, -1 when j is and 0 on a neutral court. The ridge prior
+// λ/2·Σθ² keeps undefeated and winless teams finite.
+
+type BTConfig struct {
+	Ridge   float64
+	FitHome bool
+}
+
+func DefaultBTConfig() BTConfig {
+	return BTConfig{Ridge: 0.1, FitHome: true}
+}
+
+// BTStrength is a team's log-odds strength and its standard error.
+type BTStrength struct {
+	Strength float64
+	SE       float64
+}
+
+type BTModel struct {
+	Strengths map[[2]int]BTStrength
+	Home      map[seasonKey]float64
+}
+
+// Strength returns a team-season's fitted strength.
+func (m *BTModel) Strength(season, team int) (BTStrength, bool) {
+	s, ok := m.Strengths[[2]int{season, team}]
+	return s, ok
+}
+
+// Prob is the neutral-court probability that teamA beats teamB. Teams
+// without a fit count as average (strength 0).
+func (m *BTModel) Prob(season, teamA, teamB int) float64 {
+	a := m.Strengths[[2]int{season, teamA}]
+	b := m.Strengths[[2]int{season, teamB}]
+	return sigmoid(a.Strength - b.Strength)
+}
+
+// PredictID is Prob for a submission ID such as "2026_1101_1102".
+func (m *BTModel) PredictID(id string) (float64, error) {
+	season, a, b, err := ParseMatchupID(id)
+	if err != nil {
+		return 0, err
+	}
+	return m.Prob(season, a, b), nil
+}
+
+// FitBradleyTerry fits every season and league in rows.
+func FitBradleyTerry(rows []RegularSeasonCompactRow, cfg BTConfig) (*BTModel, error) {
+	m := &BTModel{
+		Strengths: make(map[[2]int]BTStrength),
+		Home:      make(map[seasonKey]float64),
+	}
+	for _, sg := range groupSeasons(rows) {
+		theta, se, err := fitBTSeason(sg, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("bradley-terry %s %d: %w", sg.League, sg.Season, err)
+		}
+		for i, t := range sg.Teams {
+			m.Strengths[[2]int{sg.Season, t}] = BTStrength{Strength: theta[i], SE: se[i]}
+		}
+		if cfg.FitHome {
+			m.Home[seasonKey{sg.League, sg.Season}] = theta[len(sg.Teams)]
+		}
+	}
+	return m, nil
+}
+
+// fitBTSeason runs Newton's method on the penalized log-likelihood and takes
+// standard errors from the inverse Hessian at the optimum. The home term, if
+// fitted, is the last parameter and is not penalized.
+func fitBTSeason(sg *seasonGroup, cfg BTConfig) (theta, se []float64, err error) {
+	n := len(sg.Teams)
+	dim := n
+	if cfg.FitHome {
+		dim++
+	}
+	// A tiny floor keeps the Hessian invertible when Ridge is 0.
+	ridge := math.Max(cfg.Ridge, 1e-6)
+	theta = make([]float64, dim)
+
+	var chol *cholesky
+	for iter := 0; iter < 50; iter++ {
+		h := newMatrix(dim)
+		grad := make([]float64, dim)
+		for i := 0; i < n; i++ {
+			h.add(i, i, ridge)
+			grad[i] = -ridge * theta[i]
+		}
+		for _, g := range sg.Games {
+			w, l := sg.index[g.WTeamID], sg.index[g.LTeamID]
+			z := theta[w] - theta[l]
+			hs := homeSign(g.WLoc)
+			if cfg.FitHome {
+				z += hs * theta[n]
+			}
+			p := sigmoid(z)
+			r := 1 - p // winner's residual
+			v := p * (1 - p)
+			grad[w] += r
+			grad[l] -= r
+			h.add(w, w, v)
+			h.add(l, l, v)
+			h.add(w, l, -v)
+			h.add(l, w, -v)
+			if cfg.FitHome && hs != 0 {
+				grad[n] += hs * r
+				h.add(n, n, v)
+				h.add(w, n, hs*v)
+				h.add(n, w, hs*v)
+				h.add(l, n, -hs*v)
+				h.add(n, l, -hs*v)
+			}
+		}
+		if cfg.FitHome && h.at(n, n) == 0 {
+			h.add(n, n, 1) // no home games: keep the home term at 0
+		}
+		chol, err = factorCholesky(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		step := chol.solve(grad)
+		maxStep := 0.0
+		for i := range theta {
+			theta[i] += step[i]
+			maxStep = math.Max(maxStep, math.Abs(step[i]))
+		}
+		if maxStep < 1e-8 {
+			break
+		}
+	}
+
+	inv := chol.inverseDiag()
+	se = make([]float64, n)
+	for i := range se {
+		se[i] = math.Sqrt(inv[i])
+	}
+	return theta, se, nil
+}
+
+func AttachBradleyTerry(agg map[[2]int]*TeamSeasonAgg, m *BTModel) {
+	for k, s := range m.Strengths {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.BT = s.Strength
+		a.BTSE = s.SE
+	}
+}
@@ -0,0 +1,31 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBradleyTerryTwoTeams(t *testing.T) {
+	// 3-1 on neutral courts: the maximum-likelihood gap is ln 3.
+	rows := []RegularSeasonCompactRow{
+		neutralGame(1, 70, 2, 60),
+		neutralGame(1, 70, 2, 60),
+		neutralGame(1, 70, 2, 60),
+		neutralGame(2, 70, 1, 60),
+	}
+	m, err := FitBradleyTerry(rows, BTConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := m.Strength(2024, 1)
+	b, _ := m.Strength(2024, 2)
+	if gap := a.Strength - b.Strength; math.Abs(gap-math.Log(3)) > 1e-4 {
+		t.Errorf("strength gap %v, want ln 3 = %v", gap, math.Log(3))
+	}
+	if math.Abs(a.Strength+b.Strength) > 1e-6 {
+		t.Errorf("strengths %v, %v not centred on 0", a.Strength, b.Strength)
+	}
+	if p := m.Prob(2024, 1, 2); math.Abs(p-0.75) > 1e-4 {
+		t.Errorf("Prob = %v, want 0.75", p)
+	}
+}
@@ -0,0 +1,435 @@
+package mm
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The table cache stores each parsed CSV as a columnar snapshot so later runs
+// can skip encoding/csv and strconv. A snapshot is one gob stream holding a
+// CacheHeader followed by the column data; the header alone is enough to
+// decide whether the snapshot still matches its source file.
+
+const (
+	cacheVersion = 1
+	cacheExt     = ".mmc"
+)
+
+// parserVersions versions the parser of each row type. The cache key covers
+// the source file and the column layout but not how a value is parsed, so a
+// reader change that keeps the columns must bump its row type here to retire
+// the old snapshots. Unlisted row types are at version 1.
+var parserVersions = map[string]int{
+	"mm.SeedRow": 2, // strict ParseSeed in place of parseSeedNumeric
+}
+
+func parserVersion(rowType string) int {
+	if v, ok := parserVersions[rowType]; ok {
+		return v
+	}
+	return 1
+}
+
+// CacheHeader identifies the source a snapshot was built from and describes
+// its columns.
+type CacheHeader struct {
+	Version    int
+	Parser     int
+	Source     string
+	Size       int64
+	ModTime    time.Time
+	SHA256     string
+	Lenient    bool
+	Dropped    int
+	RowType    string
+	Rows       int
+	Columns    []CacheColumn
+	Created    time.Time
+	SourcePath string
+}
+
+type CacheColumn struct {
+	Name string
+	Kind string
+}
+
+// cacheColumnData holds one column. Only the slice matching the column kind
+// is populated; strings are dictionary encoded.
+type cacheColumnData struct {
+	Ints   []int64
+	Floats []float64
+	Bools  []bool
+	Dict   []string
+	Codes  []uint32
+}
+
+type cacheField struct {
+	name  string
+	index []int
+	kind  string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// cacheFields flattens the row type into leaf columns. Nested structs become
+// dotted names ("W.FGM"); a non-struct row type is a single "value" column.
+func cacheFields(t reflect.Type) ([]cacheField, error) {
+	if t.Kind() != reflect.Struct || t == timeType {
+		k, err := cacheKind(t)
+		if err != nil {
+			return nil, err
+		}
+		return []cacheField{{name: "value", kind: k}}, nil
+	}
+	var out []cacheField
+	var walk func(t reflect.Type, prefix string, index []int) error
+	walk = func(t reflect.Type, prefix string, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			idx := append(append([]int{}, index...), i)
+			if f.Type.Kind() == reflect.Struct && f.Type != timeType {
+				if err := walk(f.Type, prefix+f.Name+".", idx); err != nil {
+					return err
+				}
+				continue
+			}
+			k, err := cacheKind(f.Type)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", prefix, f.Name, err)
+			}
+			out = append(out, cacheField{name: prefix + f.Name, index: idx, kind: k})
+		}
+		return nil
+	}
+	if err := walk(t, "", nil); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func cacheKind(t reflect.Type) (string, error) {
+	if t == timeType {
+		return "time", nil
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int", nil
+	case reflect.Float32, reflect.Float64:
+		return "float", nil
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.String:
+		return "string", nil
+	}
+	return "", fmt.Errorf("unsupported cache column type %s", t)
+}
+
+func encodeColumns[T any](rows []T, fields []cacheField) []cacheColumnData {
+	cols := make([]cacheColumnData, len(fields))
+	vals := make([]reflect.Value, len(rows))
+	for i := range rows {
+		vals[i] = reflect.ValueOf(&rows[i]).Elem()
+	}
+	for ci, f := range fields {
+		c := &cols[ci]
+		codes := map[string]uint32{}
+		for _, v := range vals {
+			if f.index != nil {
+				v = v.FieldByIndex(f.index)
+			}
+			switch f.kind {
+			case "int":
+				c.Ints = append(c.Ints, v.Int())
+			case "float":
+				c.Floats = append(c.Floats, v.Float())
+			case "bool":
+				c.Bools = append(c.Bools, v.Bool())
+			case "time":
+				c.Ints = append(c.Ints, v.Interface().(time.Time).Unix())
+			case "string":
+				s := v.String()
+				code, ok := codes[s]
+				if !ok {
+					code = uint32(len(c.Dict))
+					codes[s] = code
+					c.Dict = append(c.Dict, s)
+				}
+				c.Codes = append(c.Codes, code)
+			}
+		}
+	}
+	return cols
+}
+
+func decodeColumns[T any](n int, fields []cacheField, cols []cacheColumnData) ([]T, error) {
+	if len(cols) != len(fields) {
+		return nil, fmt.Errorf("cache has %d columns, want %d", len(cols), len(fields))
+	}
+	rows := make([]T, n)
+	for ci, f := range fields {
+		c := cols[ci]
+		var have int
+		switch f.kind {
+		case "int", "time":
+			have = len(c.Ints)
+		case "float":
+			have = len(c.Floats)
+		case "bool":
+			have = len(c.Bools)
+		case "string":
+			have = len(c.Codes)
+		}
+		if have != n {
+			return nil, fmt.Errorf("cache column %s has %d values, want %d", f.name, have, n)
+		}
+		for i := range rows {
+			v := reflect.ValueOf(&rows[i]).Elem()
+			if f.index != nil {
+				v = v.FieldByIndex(f.index)
+			}
+			switch f.kind {
+			case "int":
+				v.SetInt(c.Ints[i])
+			case "float":
+				v.SetFloat(c.Floats[i])
+			case "bool":
+				v.SetBool(c.Bools[i])
+			case "time":
+				v.Set(reflect.ValueOf(time.Unix(c.Ints[i], 0).UTC()))
+			case "string":
+				if int(c.Codes[i]) >= len(c.Dict) {
+					return nil, fmt.Errorf("cache column %s: bad dictionary code", f.name)
+				}
+				v.SetString(c.Dict[c.Codes[i]])
+			}
+		}
+	}
+	return rows, nil
+}
+
+func rowTypeName[T any]() string {
+	var zero T
+	return reflect.TypeOf(zero).String()
+}
+
+func cachePath(cacheDir, source, rowType string) string {
+	rt := strings.TrimPrefix(rowType, "mm.")
+	return filepath.Join(cacheDir, filepath.Base(source)+"."+rt+cacheExt)
+}
+
+// sourceStamp is the cache key of a source file.
+type sourceStamp struct {
+	size    int64
+	modTime time.Time
+	sha256  string
+}
+
+func stampFile(fsys fs.FS, name string) (sourceStamp, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return sourceStamp{}, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return sourceStamp{}, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sourceStamp{}, err
+	}
+	return sourceStamp{
+		size:    st.Size(),
+		modTime: st.ModTime().UTC(),
+		sha256:  hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// stamp returns the stamp of member name, hashing it only the first time.
+func (l *Loader) stamp(name string) (sourceStamp, error) {
+	if s, ok := l.stamps[name]; ok {
+		return s, nil
+	}
+	fsys, err := l.source()
+	if err != nil {
+		return sourceStamp{}, err
+	}
+	s, err := stampFile(fsys, name)
+	if err != nil {
+		return sourceStamp{}, err
+	}
+	l.recordStamp(name, s)
+	return s, nil
+}
+
+func (l *Loader) recordStamp(name string, s sourceStamp) {
+	if l.stamps == nil {
+		l.stamps = make(map[string]sourceStamp)
+	}
+	l.stamps[name] = s
+}
+
+func (s sourceStamp) matches(h CacheHeader) bool {
+	return h.Size == s.size && h.ModTime.Equal(s.modTime) && h.SHA256 == s.sha256
+}
+
+func sameColumns(a []CacheColumn, fields []cacheField) bool {
+	if len(a) != len(fields) {
+		return false
+	}
+	for i, f := range fields {
+		if a[i].Name != f.name || a[i].Kind != f.kind {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadCacheHeader reads only the header of a snapshot.
+func ReadCacheHeader(path string) (CacheHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return CacheHeader{}, err
+	}
+	defer f.Close()
+	var h CacheHeader
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&h); err != nil {
+		return CacheHeader{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// ListCache returns the headers of all snapshots in cacheDir, sorted by source.
+func ListCache(cacheDir string) ([]CacheHeader, error) {
+	matches, err := filepath.Glob(filepath.Join(cacheDir, "*"+cacheExt))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]CacheHeader, 0, len(matches))
+	for _, p := range matches {
+		h, err := ReadCacheHeader(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Source != out[j].Source {
+			return out[i].Source < out[j].Source
+		}
+		return out[i].RowType < out[j].RowType
+	})
+	return out, nil
+}
+
+// loadCached returns the cached rows for source if a snapshot matching stamp
+// exists. A missing or stale snapshot is not an error.
+func loadCached[T any](l *Loader, source string, stamp sourceStamp) ([]T, CacheHeader, bool) {
+	rowType := rowTypeName[T]()
+	f, err := os.Open(cachePath(l.CacheDir, source, rowType))
+	if err != nil {
+		return nil, CacheHeader{}, false
+	}
+	defer f.Close()
+
+	fields, err := cacheFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, CacheHeader{}, false
+	}
+
+	dec := gob.NewDecoder(bufio.NewReader(f))
+	var h CacheHeader
+	if err := dec.Decode(&h); err != nil {
+		return nil, CacheHeader{}, false
+	}
+	if h.Version != cacheVersion || h.Parser != parserVersion(rowType) || h.RowType != rowType ||
+		!stamp.matches(h) || !sameColumns(h.Columns, fields) {
+		return nil, CacheHeader{}, false
+	}
+	// A lenient snapshot that dropped rows must not satisfy a strict read.
+	if h.Lenient != l.Lenient && h.Dropped > 0 {
+		return nil, CacheHeader{}, false
+	}
+	var cols []cacheColumnData
+	if err := dec.Decode(&cols); err != nil {
+		return nil, CacheHeader{}, false
+	}
+	rows, err := decodeColumns[T](h.Rows, fields, cols)
+	if err != nil {
+		return nil, CacheHeader{}, false
+	}
+	return rows, h, true
+}
+
+// storeCached writes a snapshot of rows. Row types the codec cannot encode
+// are silently not cached.
+func storeCached[T any](l *Loader, source string, stamp sourceStamp, rows []T, dropped int) error {
+	fields, err := cacheFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(l.CacheDir, 0o755); err != nil {
+		return err
+	}
+	rowType := rowTypeName[T]()
+	h := CacheHeader{
+		Version:    cacheVersion,
+		Parser:     parserVersion(rowType),
+		Source:     filepath.Base(source),
+		Size:       stamp.size,
+		ModTime:    stamp.modTime,
+		SHA256:     stamp.sha256,
+		Lenient:    l.Lenient,
+		Dropped:    dropped,
+		RowType:    rowType,
+		Rows:       len(rows),
+		Created:    time.Now().UTC(),
+		SourcePath: source,
+	}
+	for _, f := range fields {
+		h.Columns = append(h.Columns, CacheColumn{Name: f.name, Kind: f.kind})
+	}
+
+	path := cachePath(l.CacheDir, source, rowType)
+	tmp, err := os.CreateTemp(l.CacheDir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	bw := bufio.NewWriter(tmp)
+	enc := gob.NewEncoder(bw)
+	if err := enc.Encode(h); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := enc.Encode(encodeColumns(rows, fields)); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,45 @@
+package mm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheParserVersion(t *testing.T) {
+	dir := t.TempDir()
+	csv := "Season,Seed,TeamID\n2024,W01,1101\n2024,X16a,1102\n"
+	if err := os.WriteFile(filepath.Join(dir, "MNCAATourneySeeds.csv"), []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func() ([]SeedRow, int) {
+		l := NewLoader(dir)
+		defer l.Close()
+		l.CacheDir = filepath.Join(dir, "cache")
+		rows, err := l.Seeds(LeagueMen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rows, l.CacheHits
+	}
+
+	first, hits := read()
+	if hits != 0 || len(first) != 2 {
+		t.Fatalf("first read: %d rows, %d cache hits", len(first), hits)
+	}
+	if _, hits := read(); hits != 1 {
+		t.Fatalf("second read: %d cache hits, want 1", hits)
+	}
+
+	old := parserVersions["mm.SeedRow"]
+	parserVersions["mm.SeedRow"] = old + 1
+	defer func() { parserVersions["mm.SeedRow"] = old }()
+	rows, hits := read()
+	if hits != 0 {
+		t.Errorf("snapshot from an older parser was served")
+	}
+	if len(rows) != len(first) || rows[1].Seed != 16 {
+		t.Errorf("reparsed rows %+v", rows)
+	}
+}
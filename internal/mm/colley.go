@@ -0,0 +1,50 @@
+package mm
+
+import "fmt"
+
+// Colley matrix ratings (Colley 2002), fitted per season and league from
+// wins and losses only: C·r = b with C = 2I + diag(games) - N, where N counts
+// the games between each pair, and b = 1 + (wins - losses)/2. Ratings centre
+// on 0.5.
+
+// BuildColley rates every team-season in rows.
+func BuildColley(rows []RegularSeasonCompactRow) (map[[2]int]float64, error) {
+	out := make(map[[2]int]float64)
+	for _, sg := range groupSeasons(rows) {
+		n := len(sg.Teams)
+		c := newMatrix(n)
+		c.addDiag(2)
+		b := make([]float64, n)
+		for i := range b {
+			b[i] = 1
+		}
+		for _, g := range sg.Games {
+			w, l := sg.index[g.WTeamID], sg.index[g.LTeamID]
+			c.add(w, w, 1)
+			c.add(l, l, 1)
+			c.add(w, l, -1)
+			c.add(l, w, -1)
+			b[w] += 0.5
+			b[l] -= 0.5
+		}
+		r, err := solveSPD(c, b)
+		if err != nil {
+			return nil, fmt.Errorf("colley %s %d: %w", sg.League, sg.Season, err)
+		}
+		for i, t := range sg.Teams {
+			out[[2]int{sg.Season, t}] = r[i]
+		}
+	}
+	return out, nil
+}
+
+func AttachColley(agg map[[2]int]*TeamSeasonAgg, ratings map[[2]int]float64) {
+	for k, r := range ratings {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.Colley = r
+	}
+}
@@ -0,0 +1,33 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestColley(t *testing.T) {
+	// A cycle is a balanced schedule: everyone is 1-1.
+	cycle := []RegularSeasonCompactRow{
+		neutralGame(1, 70, 2, 60),
+		neutralGame(2, 70, 3, 60),
+		neutralGame(3, 70, 1, 60),
+	}
+	got, err := BuildColley(cycle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for team := 1; team <= 3; team++ {
+		if r := got[[2]int{2024, team}]; math.Abs(r-0.5) > 1e-12 {
+			t.Errorf("team %d rating %v, want 0.5", team, r)
+		}
+	}
+
+	// One game: C = [3 -1; -1 3], b = [1.5 0.5], so r = [5/8 3/8].
+	got, err = BuildColley([]RegularSeasonCompactRow{neutralGame(1, 70, 2, 60)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, l := got[[2]int{2024, 1}], got[[2]int{2024, 2}]; math.Abs(w-0.625) > 1e-12 || math.Abs(l-0.375) > 1e-12 {
+		t.Errorf("single game ratings %v, %v, want 0.625, 0.375", w, l)
+	}
+}
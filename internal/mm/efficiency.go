@@ -0,0 +1,150 @@
+package mm
+
+import "math"
+
+// Tempo-free team ratings from box scores, in the style of KenPom: points per
+// 100 possessions on offense and defense and possessions per 40 minutes,
+// each iteratively adjusted for the strength of the opponents faced.
+
+type EfficiencyConfig struct {
+	// HomeEdge is the fraction by which playing at home raises a team's
+	// offensive efficiency and lowers the efficiency it allows.
+	HomeEdge float64
+	MaxIter  int
+	Tol      float64
+}
+
+func DefaultEfficiencyConfig() EfficiencyConfig {
+	return EfficiencyConfig{HomeEdge: 0.014, MaxIter: 100, Tol: 1e-6}
+}
+
+// Efficiency is a team-season's adjusted ratings; AdjEM = AdjO - AdjD.
+type Efficiency struct {
+	AdjO     float64
+	AdjD     float64
+	AdjEM    float64
+	AdjTempo float64
+}
+
+// Possessions estimates a team's possessions in a game from its box score.
+func Possessions(b BoxScore) float64 {
+	return float64(b.FGA-b.OR+b.TO) + 0.475*float64(b.FTA)
+}
+
+// effGame is one team's view of a game, already corrected for home court.
+type effGame struct {
+	opp   int
+	off   float64 // points scored per 100 possessions
+	def   float64 // points allowed per 100 possessions
+	tempo float64 // possessions per 40 minutes
+}
+
+// BuildEfficiency rates every team-season in rows, separately per league.
+func BuildEfficiency(rows []DetailedResultRow, cfg EfficiencyConfig) map[[2]int]Efficiency {
+	byGroup := map[seasonKey][]DetailedResultRow{}
+	for _, g := range rows {
+		k := seasonKey{League: LeagueOfTeam(g.WTeamID), Season: g.Season}
+		byGroup[k] = append(byGroup[k], g)
+	}
+	out := make(map[[2]int]Efficiency)
+	for k, games := range byGroup {
+		for team, e := range adjustEfficiency(games, cfg) {
+			out[[2]int{k.Season, team}] = e
+		}
+	}
+	return out
+}
+
+func AttachEfficiency(agg map[[2]int]*TeamSeasonAgg, eff map[[2]int]Efficiency) {
+	for k, e := range eff {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.AdjO = e.AdjO
+		a.AdjD = e.AdjD
+		a.AdjEM = e.AdjEM
+		a.AdjTempo = e.AdjTempo
+	}
+}
+
+func adjustEfficiency(rows []DetailedResultRow, cfg EfficiencyConfig) map[int]Efficiency {
+	games := map[int][]effGame{}
+	var points, poss, tempo float64
+	var n int
+	for _, g := range rows {
+		// Both teams play the same number of possessions; average the two
+		// estimates to cut the noise.
+		p := (Possessions(g.W) + Possessions(g.L)) / 2
+		if p <= 0 {
+			continue
+		}
+		t := p * 40 / (40 + 5*float64(g.NumOT))
+		wOff := 100 * float64(g.WScore) / p
+		lOff := 100 * float64(g.LScore) / p
+
+		// Undo the home edge so every game reads as if played on a neutral court.
+		hw, hl := 1.0, 1.0
+		switch g.WLoc {
+		case "H":
+			hw, hl = 1+cfg.HomeEdge, 1/(1+cfg.HomeEdge)
+		case "A":
+			hw, hl = 1/(1+cfg.HomeEdge), 1+cfg.HomeEdge
+		}
+		games[g.WTeamID] = append(games[g.WTeamID], effGame{opp: g.LTeamID, off: wOff / hw, def: lOff * hw, tempo: t})
+		games[g.LTeamID] = append(games[g.LTeamID], effGame{opp: g.WTeamID, off: lOff / hl, def: wOff * hl, tempo: t})
+
+		points += float64(g.WScore + g.LScore)
+		poss += 2 * p
+		tempo += t
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	avgE := 100 * points / poss
+	avgT := tempo / float64(n)
+
+	cur := make(map[int]Efficiency, len(games))
+	for t, gs := range games {
+		var e Efficiency
+		for _, g := range gs {
+			e.AdjO += g.off
+			e.AdjD += g.def
+			e.AdjTempo += g.tempo
+		}
+		n := float64(len(gs))
+		cur[t] = Efficiency{AdjO: e.AdjO / n, AdjD: e.AdjD / n, AdjTempo: e.AdjTempo / n}
+	}
+
+	for iter := 0; iter < cfg.MaxIter; iter++ {
+		next := make(map[int]Efficiency, len(cur))
+		maxDiff := 0.0
+		for t, gs := range games {
+			var e Efficiency
+			for _, g := range gs {
+				o := cur[g.opp]
+				e.AdjO += g.off * avgE / o.AdjD
+				e.AdjD += g.def * avgE / o.AdjO
+				e.AdjTempo += g.tempo * avgT / o.AdjTempo
+			}
+			n := float64(len(gs))
+			e.AdjO /= n
+			e.AdjD /= n
+			e.AdjTempo /= n
+			c := cur[t]
+			maxDiff = math.Max(maxDiff, math.Max(math.Abs(e.AdjO-c.AdjO), math.Abs(e.AdjD-c.AdjD)))
+			next[t] = e
+		}
+		cur = next
+		if maxDiff < cfg.Tol {
+			break
+		}
+	}
+	for t, e := range cur {
+		e.AdjEM = e.AdjO - e.AdjD
+		cur[t] = e
+	}
+	return cur
+}
@@ -1,48 +1,256 @@
 package mm
 
-import "math"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
 
 type EloConfig struct {
-	K     float64
-	Start float64
+	K     float64 `json:"k"`
+	Start float64 `json:"start"`
+
+	// HomeAdv is added to the home team's rating when computing the
+	// expected score. Neutral-site games get no adjustment.
+	HomeAdv float64 `json:"home_adv"`
+	// MOVExponent enables the FiveThirtyEight margin-of-victory multiplier
+	// (MOV+3)^MOVExponent / (7.5 + 0.006*EloDiff), where EloDiff is the
+	// winner's pre-game edge. The denominator corrects for autocorrelation:
+	// favourites are expected to win big, so their margins count for less.
+	// Zero disables the multiplier.
+	MOVExponent float64 `json:"mov_exponent"`
+	// OTDamp shrinks the update of overtime games by this fraction, since
+	// they were tied after regulation.
+	OTDamp float64 `json:"ot_damp"`
+
+	// Carryover starts each season from the team's previous final rating
+	// instead of Start, so ratings form one multi-season history.
+	Carryover bool `json:"carryover"`
+	// Regress pulls a carried rating this fraction of the way back to the
+	// league mean (or conference mean with RegressToConf) between seasons.
+	Regress       float64 `json:"regress"`
+	RegressToConf bool    `json:"regress_to_conf"`
+	// NewTeam is the first rating of a program with no earlier season when
+	// carrying over; zero means Start.
+	NewTeam float64 `json:"new_team"`
 }
 
+// DefaultEloConfig is the original fixed-K Elo. The home-court, margin and
+// overtime terms stay off until a tune_elo run shows they help; pass them with
+// --elo_config or the elo_* flags.
 func DefaultEloConfig() EloConfig {
-	return EloConfig{K: 20.0, Start: 1500.0}
+	return EloConfig{
+		K: 20.0, Start: 1500.0,
+		Regress: 0.25, NewTeam: 1500.0,
+	}
+}
+
+func SaveEloConfigJSON(path string, cfg EloConfig) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// LoadEloConfigJSON reads a config written by SaveEloConfigJSON; fields the
+// file leaves out keep their DefaultEloConfig values.
+func LoadEloConfigJSON(path string) (EloConfig, error) {
+	cfg := DefaultEloConfig()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
 }
 
 func expectedScore(ra, rb float64) float64 {
 	return 1.0 / (1.0 + math.Pow(10.0, (rb-ra)/400.0))
 }
 
+// homeAdjust returns the rating bonus of the winner and the loser.
+func homeAdjust(wLoc string, adv float64) (w, l float64) {
+	switch wLoc {
+	case "H":
+		return adv, 0
+	case "A":
+		return 0, adv
+	}
+	return 0, 0
+}
+
+// movMultiplier scales K by the margin of victory; winnerEdge is the winner's
+// pre-game rating advantage including home court.
+func (cfg EloConfig) movMultiplier(margin int, winnerEdge float64) float64 {
+	if cfg.MOVExponent <= 0 {
+		return 1
+	}
+	den := 7.5 + 0.006*winnerEdge
+	if den < 1 {
+		den = 1
+	}
+	return math.Pow(float64(margin)+3, cfg.MOVExponent) / den
+}
+
+// eloDelta is the rating points the winner takes from the loser.
+func (cfg EloConfig) eloDelta(g RegularSeasonCompactRow, rw, rl float64) float64 {
+	hw, hl := homeAdjust(g.WLoc, cfg.HomeAdv)
+	ew := expectedScore(rw+hw, rl+hl)
+	d := cfg.K * (1.0 - ew) * cfg.movMultiplier(g.WScore-g.LScore, (rw+hw)-(rl+hl))
+	if g.NumOT > 0 {
+		d *= 1 - cfg.OTDamp
+	}
+	return d
+}
+
+// sortedGames returns the games ordered by season and day, keeping the file
+// order of same-day games.
+func sortedGames(rows []RegularSeasonCompactRow) []RegularSeasonCompactRow {
+	games := append([]RegularSeasonCompactRow{}, rows...)
+	sort.SliceStable(games, func(i, j int) bool {
+		if games[i].Season != games[j].Season {
+			return games[i].Season < games[j].Season
+		}
+		return games[i].DayNum < games[j].DayNum
+	})
+	return games
+}
+
+// eloCarry decides the first rating of a team in a season.
+type eloCarry struct {
+	cfg  EloConfig
+	meta *TeamMeta
+
+	last       map[int]float64 // team -> latest end-of-season rating
+	lastConf   map[int]string  // team -> conference in that season
+	leagueMean map[League]float64
+	confMean   map[confKey]float64
+}
+
+type confKey struct {
+	League League
+	Conf   string
+}
+
+func newEloCarry(cfg EloConfig, meta *TeamMeta) *eloCarry {
+	return &eloCarry{
+		cfg:        cfg,
+		meta:       meta,
+		last:       make(map[int]float64),
+		lastConf:   make(map[int]string),
+		leagueMean: make(map[League]float64),
+		confMean:   make(map[confKey]float64),
+	}
+}
+
+// endSeason records the final ratings of season and the means they regress to.
+func (c *eloCarry) endSeason(season int, rating map[[2]int]float64) {
+	leagueSum := map[League][2]float64{}
+	confSum := map[confKey][2]float64{}
+	for k, r := range rating {
+		if k[0] != season {
+			continue
+		}
+		team := k[1]
+		lg := LeagueOfTeam(team)
+		c.last[team] = r
+		ls := leagueSum[lg]
+		leagueSum[lg] = [2]float64{ls[0] + r, ls[1] + 1}
+
+		delete(c.lastConf, team)
+		if c.meta != nil {
+			if conf, ok := c.meta.Conference(season, team); ok {
+				c.lastConf[team] = conf
+				ck := confKey{lg, conf}
+				cs := confSum[ck]
+				confSum[ck] = [2]float64{cs[0] + r, cs[1] + 1}
+			}
+		}
+	}
+	for lg, s := range leagueSum {
+		c.leagueMean[lg] = s[0] / s[1]
+	}
+	for ck, s := range confSum {
+		c.confMean[ck] = s[0] / s[1]
+	}
+}
+
+func (c *eloCarry) start(team int) float64 {
+	if !c.cfg.Carryover {
+		return c.cfg.Start
+	}
+	prev, ok := c.last[team]
+	if !ok {
+		if c.cfg.NewTeam != 0 {
+			return c.cfg.NewTeam
+		}
+		return c.cfg.Start
+	}
+	lg := LeagueOfTeam(team)
+	target := c.leagueMean[lg]
+	if c.cfg.RegressToConf {
+		if m, ok := c.confMean[confKey{lg, c.lastConf[team]}]; ok {
+			target = m
+		}
+	}
+	return prev + c.cfg.Regress*(target-prev)
+}
+
 func BuildEloEnd(rows []RegularSeasonCompactRow, cfg EloConfig) map[[2]int]float64 {
+	return BuildEloEndConf(rows, cfg, nil)
+}
+
+// BuildEloEndConf is BuildEloEnd with conference membership, which
+// cfg.RegressToConf needs; meta may be nil otherwise.
+func BuildEloEndConf(rows []RegularSeasonCompactRow, cfg EloConfig, meta *TeamMeta) map[[2]int]float64 {
+	return BuildEloHistory(rows, cfg, meta).End()
+}
+
+// BuildEloHistory runs Elo over the games in season/day order and records
+// every team's rating after each day it played.
+func BuildEloHistory(rows []RegularSeasonCompactRow, cfg EloConfig, meta *TeamMeta) *RatingHistory {
+	hist := NewRatingHistory()
 	rating := make(map[[2]int]float64)
+	carry := newEloCarry(cfg, meta)
 
 	get := func(season, team int) float64 {
 		k := [2]int{season, team}
 		v, ok := rating[k]
 		if !ok {
-			v = cfg.Start
+			v = carry.start(team)
 			rating[k] = v
+			hist.Begin(season, team, v)
 		}
 		return v
 	}
-	set := func(season, team int, v float64) {
+	set := func(season, team, day int, v float64) {
 		rating[[2]int{season, team}] = v
+		hist.Record(season, team, day, v)
 	}
 
-	for _, g := range rows {
+	season := 0
+	for i, g := range sortedGames(rows) {
+		if i > 0 && g.Season != season {
+			carry.endSeason(season, rating)
+		}
+		season = g.Season
+
 		ra := get(g.Season, g.WTeamID)
 		rb := get(g.Season, g.LTeamID)
 
-		ea := expectedScore(ra, rb)
-		eb := 1.0 - ea
-
-		ra2 := ra + cfg.K*(1.0-ea)
-		rb2 := rb + cfg.K*(0.0-eb)
+		d := cfg.eloDelta(g, ra, rb)
 
-		set(g.Season, g.WTeamID, ra2)
-		set(g.Season, g.LTeamID, rb2)
+		set(g.Season, g.WTeamID, g.DayNum, ra+d)
+		set(g.Season, g.LTeamID, g.DayNum, rb-d)
 	}
-	return rating
+	return hist
 }
@@ -0,0 +1,94 @@
+package mm
+
+import "sort"
+
+// EloGrid lists the values to try for each tuned EloConfig field. A Regress
+// value of 1 or more turns carryover off; smaller values carry ratings over.
+// An empty Regress grid keeps the base config's Carryover and Regress.
+type EloGrid struct {
+	K           []float64
+	HomeAdv     []float64
+	MOVExponent []float64
+	Regress     []float64
+}
+
+// Configs expands the grid over base, which supplies the untuned fields.
+func (g EloGrid) Configs(base EloConfig) []EloConfig {
+	orBase := func(vals []float64, v float64) []float64 {
+		if len(vals) == 0 {
+			return []float64{v}
+		}
+		return vals
+	}
+	var out []EloConfig
+	for _, k := range orBase(g.K, base.K) {
+		for _, h := range orBase(g.HomeAdv, base.HomeAdv) {
+			for _, mov := range orBase(g.MOVExponent, base.MOVExponent) {
+				cfg := base
+				cfg.K, cfg.HomeAdv, cfg.MOVExponent = k, h, mov
+				if len(g.Regress) == 0 {
+					out = append(out, cfg)
+					continue
+				}
+				for _, reg := range g.Regress {
+					cfg.Carryover = reg < 1
+					cfg.Regress = reg
+					out = append(out, cfg)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// EloTrial is the cross-validated score of one config.
+type EloTrial struct {
+	Config    EloConfig
+	Brier     float64
+	FoldBrier []float64
+}
+
+// ScoreEloConfig rates the regular season with cfg and scores an Elo-only
+// logistic regression on the tournament games with LOSO folds.
+func ScoreEloConfig(regular []RegularSeasonCompactRow, tourney []TourneyCompactRow, meta *TeamMeta, cfg EloConfig) EloTrial {
+	elo := BuildEloEndConf(regular, cfg, meta)
+	rows := BuildTrainMatchupsFromTourney(tourney)
+	x := make([][]float64, len(rows))
+	y := make([]float64, len(rows))
+	for i := range rows {
+		r := rows[i]
+		d := elo[[2]int{r.Season, r.TeamA}] - elo[[2]int{r.Season, r.TeamB}]
+		x[i] = []float64{d}
+		y[i] = r.Label
+	}
+
+	trial := EloTrial{Config: cfg}
+	for _, fold := range LOSOFolds(rows) {
+		var xtr [][]float64
+		var ytr []float64
+		for _, i := range fold.TrainIdx {
+			xtr = append(xtr, x[i])
+			ytr = append(ytr, y[i])
+		}
+		model := TrainLogReg(xtr, ytr, []string{"DElo"}, DefaultTrainConfig())
+		yva := make([]float64, len(fold.ValIdx))
+		pred := make([]float64, len(fold.ValIdx))
+		for j, i := range fold.ValIdx {
+			yva[j] = y[i]
+			pred[j] = model.PredictProba(x[i])
+		}
+		trial.FoldBrier = append(trial.FoldBrier, BrierScore(yva, pred))
+	}
+	trial.Brier, _ = MeanStd(trial.FoldBrier)
+	return trial
+}
+
+// TuneElo scores every config and returns the trials, best first.
+func TuneElo(regular []RegularSeasonCompactRow, tourney []TourneyCompactRow, meta *TeamMeta, configs []EloConfig) []EloTrial {
+	trials := make([]EloTrial, 0, len(configs))
+	for _, cfg := range configs {
+		trials = append(trials, ScoreEloConfig(regular, tourney, meta, cfg))
+	}
+	sort.SliceStable(trials, func(i, j int) bool { return trials[i].Brier < trials[j].Brier })
+	return trials
+}
@@ -76,6 +76,39 @@ func BuildTeamSeasonAgg(
 	return agg
 }
 
+// GamesThrough keeps the games played on or before day of their season.
+func GamesThrough(rows []RegularSeasonCompactRow, day int) []RegularSeasonCompactRow {
+	var out []RegularSeasonCompactRow
+	for _, g := range rows {
+		if g.DayNum <= day {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// MasseyThrough keeps the rankings published on or before day.
+func MasseyThrough(rows []MasseyRow, day int) []MasseyRow {
+	var out []MasseyRow
+	for _, m := range rows {
+		if m.RankingDay <= day {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// DetailedThrough keeps the detailed results played on or before day.
+func DetailedThrough(rows []DetailedResultRow, day int) []DetailedResultRow {
+	var out []DetailedResultRow
+	for _, g := range rows {
+		if g.DayNum <= day {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
 func AttachEloEnd(agg map[[2]int]*TeamSeasonAgg, eloEnd map[[2]int]float64) {
 	for k, r := range eloEnd {
 		a, ok := agg[k]
@@ -87,37 +120,41 @@ func AttachEloEnd(agg map[[2]int]*TeamSeasonAgg, eloEnd map[[2]int]float64) {
 	}
 }
 
+// WriteTeamSeasonAggCSV writes the identifying columns and game counts of
+// every team-season followed by the registered team features.
 func WriteTeamSeasonAggCSV(outDir string, agg map[[2]int]*TeamSeasonAgg) (string, error) {
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return "", err
 	}
-	path := filepath.Join(outDir, "team_season_features.csv")
-	w, err := NewCSVWriter(path, []string{
-		"Season", "TeamID",
+	features := TeamFeatures()
+	header := []string{
+		"Season", "TeamID", "TeamName", "ConfAbbrev",
 		"Games", "Wins", "Losses",
-		"WinPct", "AvgPF", "AvgPA", "AvgMargin",
-		"EloEnd", "Seed", "MasseyOrdinal",
-	})
+	}
+	for _, f := range features {
+		header = append(header, f.Name)
+	}
+	path := filepath.Join(outDir, "team_season_features.csv")
+	w, err := NewCSVWriter(path, header)
 	if err != nil {
 		return "", err
 	}
 	defer w.Close()
 
 	for _, a := range agg {
-		w.WriteRow([]string{
+		row := []string{
 			fmtInt(a.Season),
 			fmtInt(a.TeamID),
+			a.TeamName,
+			a.ConfAbbrev,
 			fmtInt(a.Games),
 			fmtInt(a.Wins),
 			fmtInt(a.Losses),
-			fmtF(a.WinPct),
-			fmtF(a.AvgPF),
-			fmtF(a.AvgPA),
-			fmtF(a.AvgMargin),
-			fmtF(a.EloEnd),
-			fmtF(a.Seed),
-			fmtF(a.MasseyOrdinal),
-		})
+		}
+		for _, f := range features {
+			row = append(row, fmtF(f.of(a)))
+		}
+		w.WriteRow(row)
 	}
 	return path, nil
 }
@@ -0,0 +1,154 @@
+package mm
+
+import (
+	"math"
+	"sort"
+)
+
+// Recent-form aggregates of a team-season: results over its last games and
+// over the last days before EndDay, and exponentially time-decayed margin
+// and efficiency.
+
+type FormConfig struct {
+	// EndDay is the last DayNum that counts, Selection Sunday by default.
+	EndDay int
+	// LastGames is the size of the last-games window; 0 disables it.
+	LastGames int
+	// LastDays is the size of the last-days window ending at EndDay; 0
+	// disables it.
+	LastDays int
+	// HalfLife, in days before EndDay, of the decayed averages; 0 disables
+	// them.
+	HalfLife float64
+}
+
+// SelectionSunday is the DayNum of Selection Sunday in the Kaggle data.
+const SelectionSunday = 132
+
+func DefaultFormConfig() FormConfig {
+	return FormConfig{EndDay: SelectionSunday, LastGames: 10, LastDays: 30, HalfLife: 14}
+}
+
+type Form struct {
+	GamesWinPct float64 // last LastGames games
+	GamesMargin float64
+	GamesElo    float64 // rating change over those games
+
+	DaysWinPct float64 // last LastDays days
+	DaysMargin float64
+	DaysElo    float64
+
+	DecayMargin float64
+	DecayEff    float64 // net points per 100 possessions; needs detailed results
+}
+
+type formGame struct {
+	day    int
+	won    bool
+	margin float64
+	eff    float64 // net efficiency, NaN without a box score
+}
+
+// BuildForm computes recent form from the regular season. detailed may be
+// nil, leaving DecayEff at 0; hist supplies the rating changes and may be
+// nil too.
+func BuildForm(rows []RegularSeasonCompactRow, detailed []DetailedResultRow, hist *RatingHistory, cfg FormConfig) map[[2]int]Form {
+	poss := make(map[[4]int]float64, len(detailed))
+	for _, g := range detailed {
+		poss[[4]int{g.Season, g.DayNum, g.WTeamID, g.LTeamID}] = (Possessions(g.W) + Possessions(g.L)) / 2
+	}
+
+	games := map[[2]int][]formGame{}
+	for _, g := range sortedGames(rows) {
+		if g.DayNum > cfg.EndDay {
+			continue
+		}
+		m := float64(g.WScore - g.LScore)
+		eff := math.NaN()
+		if p, ok := poss[[4]int{g.Season, g.DayNum, g.WTeamID, g.LTeamID}]; ok && p > 0 {
+			eff = 100 * m / p
+		}
+		w := [2]int{g.Season, g.WTeamID}
+		l := [2]int{g.Season, g.LTeamID}
+		games[w] = append(games[w], formGame{day: g.DayNum, won: true, margin: m, eff: eff})
+		games[l] = append(games[l], formGame{day: g.DayNum, won: false, margin: -m, eff: -eff})
+	}
+
+	ratingChange := func(k [2]int, from, to int) float64 {
+		if hist == nil {
+			return 0
+		}
+		a, _ := hist.RatingAsOf(k[0], k[1], from)
+		b, _ := hist.RatingAsOf(k[0], k[1], to)
+		return b - a
+	}
+
+	out := make(map[[2]int]Form, len(games))
+	for k, gs := range games {
+		var f Form
+		if cfg.LastGames > 0 {
+			win := gs[max(0, len(gs)-cfg.LastGames):]
+			f.GamesWinPct, f.GamesMargin = formRecord(win)
+			f.GamesElo = ratingChange(k, win[0].day-1, win[len(win)-1].day)
+		}
+		if cfg.LastDays > 0 {
+			start := cfg.EndDay - cfg.LastDays
+			i := sort.Search(len(gs), func(i int) bool { return gs[i].day > start })
+			f.DaysWinPct, f.DaysMargin = formRecord(gs[i:])
+			f.DaysElo = ratingChange(k, start, cfg.EndDay)
+		}
+		if cfg.HalfLife > 0 {
+			var wm, sw, we, swe float64
+			for _, g := range gs {
+				w := math.Exp2(-float64(cfg.EndDay-g.day) / cfg.HalfLife)
+				wm += w * g.margin
+				sw += w
+				if !math.IsNaN(g.eff) {
+					we += w * g.eff
+					swe += w
+				}
+			}
+			f.DecayMargin = wm / sw
+			if swe > 0 {
+				f.DecayEff = we / swe
+			}
+		}
+		out[k] = f
+	}
+	return out
+}
+
+// formRecord returns the win pct and average margin of games, zero if empty.
+func formRecord(games []formGame) (winPct, margin float64) {
+	if len(games) == 0 {
+		return 0, 0
+	}
+	wins := 0
+	for _, g := range games {
+		if g.won {
+			wins++
+		}
+		margin += g.margin
+	}
+	n := float64(len(games))
+	return float64(wins) / n, margin / n
+}
+
+// AttachForm stores the form features in each team's Extra values.
+func AttachForm(agg map[[2]int]*TeamSeasonAgg, form map[[2]int]Form) {
+	for k, f := range form {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.Set("FormWinPct", f.GamesWinPct)
+		a.Set("FormMargin", f.GamesMargin)
+		a.Set("FormElo", f.GamesElo)
+		a.Set("DaysWinPct", f.DaysWinPct)
+		a.Set("DaysMargin", f.DaysMargin)
+		a.Set("DaysElo", f.DaysElo)
+		a.Set("DecayMargin", f.DecayMargin)
+		a.Set("DecayEff", f.DecayEff)
+	}
+}
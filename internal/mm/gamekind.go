@@ -0,0 +1,128 @@
+package mm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GameKind classifies a game so features and ratings can weight or exclude it.
+type GameKind string
+
+const (
+	GameUntagged      GameKind = ""
+	GameConference    GameKind = "conf"
+	GameNonConference GameKind = "nonconf"
+	GameConfTourney   GameKind = "conftourney"
+	GameSecondary     GameKind = "secondary"
+)
+
+var gameKinds = []GameKind{GameConference, GameNonConference, GameConfTourney, GameSecondary}
+
+// ParseGameKinds parses a comma separated list such as "nonconf,conftourney".
+func ParseGameKinds(s string) ([]GameKind, error) {
+	var out []GameKind
+	for _, p := range strings.Split(s, ",") {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		ok := false
+		for _, k := range gameKinds {
+			if GameKind(p) == k {
+				ok = true
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown game kind %q (want one of %v)", p, gameKinds)
+		}
+		out = append(out, GameKind(p))
+	}
+	return out, nil
+}
+
+// TagGames sets Kind on every regular-season row: conference tournament games
+// come from confTourney, the rest are conference or non-conference games by
+// the teams' membership in meta. Rows whose teams have no known conference
+// stay untagged; rows already tagged (e.g. GameSecondary) are left alone.
+func TagGames(rows []RegularSeasonCompactRow, meta *TeamMeta, confTourney []ConferenceTourneyGameRow) {
+	inConfTourney := make(map[[4]int]bool, len(confTourney))
+	for _, g := range confTourney {
+		inConfTourney[[4]int{g.Season, g.DayNum, g.WTeamID, g.LTeamID}] = true
+	}
+	for i := range rows {
+		g := &rows[i]
+		if g.Kind != GameUntagged {
+			continue
+		}
+		if inConfTourney[[4]int{g.Season, g.DayNum, g.WTeamID, g.LTeamID}] {
+			g.Kind = GameConfTourney
+			continue
+		}
+		if meta == nil {
+			continue
+		}
+		_, okW := meta.Conference(g.Season, g.WTeamID)
+		_, okL := meta.Conference(g.Season, g.LTeamID)
+		switch {
+		case !okW || !okL:
+		case meta.SameConference(g.Season, g.WTeamID, g.LTeamID):
+			g.Kind = GameConference
+		default:
+			g.Kind = GameNonConference
+		}
+	}
+}
+
+// FilterGames drops the rows whose Kind is in exclude.
+func FilterGames(rows []RegularSeasonCompactRow, exclude ...GameKind) []RegularSeasonCompactRow {
+	if len(exclude) == 0 {
+		return rows
+	}
+	drop := make(map[GameKind]bool, len(exclude))
+	for _, k := range exclude {
+		drop[k] = true
+	}
+	out := make([]RegularSeasonCompactRow, 0, len(rows))
+	for _, g := range rows {
+		if !drop[g.Kind] {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// FilterDetailed drops the detailed rows of the compact games whose Kind is
+// in exclude, so box-score ratings use the same games as FilterGames. Rows are
+// matched by season, day and teams; detailed rows with no tagged compact row
+// are kept.
+func FilterDetailed(detailed []DetailedResultRow, compact []RegularSeasonCompactRow, exclude ...GameKind) []DetailedResultRow {
+	if len(exclude) == 0 {
+		return detailed
+	}
+	drop := make(map[GameKind]bool, len(exclude))
+	for _, k := range exclude {
+		drop[k] = true
+	}
+	dropped := make(map[[4]int]bool)
+	for _, g := range compact {
+		if drop[g.Kind] {
+			dropped[[4]int{g.Season, g.DayNum, g.WTeamID, g.LTeamID}] = true
+		}
+	}
+	out := make([]DetailedResultRow, 0, len(detailed))
+	for _, g := range detailed {
+		if !dropped[[4]int{g.Season, g.DayNum, g.WTeamID, g.LTeamID}] {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// CountGameKinds tallies rows per Kind.
+func CountGameKinds(rows []RegularSeasonCompactRow) map[GameKind]int {
+	out := make(map[GameKind]int)
+	for _, g := range rows {
+		out[g.Kind]++
+	}
+	return out
+}
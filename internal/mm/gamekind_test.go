@@ -0,0 +1,23 @@
+package mm
+
+import "testing"
+
+func TestFilterDetailed(t *testing.T) {
+	compact := []RegularSeasonCompactRow{
+		{Season: 2024, DayNum: 10, WTeamID: 1101, LTeamID: 1102, Kind: GameConference},
+		{Season: 2024, DayNum: 130, WTeamID: 1101, LTeamID: 1103, Kind: GameConfTourney},
+	}
+	detailed := []DetailedResultRow{
+		{Season: 2024, DayNum: 10, WTeamID: 1101, LTeamID: 1102},
+		{Season: 2024, DayNum: 130, WTeamID: 1101, LTeamID: 1103},
+		{Season: 2024, DayNum: 131, WTeamID: 1104, LTeamID: 1105}, // no compact row
+	}
+
+	got := FilterDetailed(detailed, compact, GameConfTourney)
+	if len(got) != 2 || got[0].DayNum != 10 || got[1].DayNum != 131 {
+		t.Errorf("FilterDetailed kept %+v", got)
+	}
+	if got := FilterDetailed(detailed, compact); len(got) != len(detailed) {
+		t.Errorf("FilterDetailed with no kinds kept %d of %d rows", len(got), len(detailed))
+	}
+}
@@ -0,0 +1,166 @@
+package mm
+
+import "math"
+
+// Glicko-2 (Glickman, "Example of the Glicko-2 system"). Every DayNum of a
+// season is one rating period: a team's games of that day are scored against
+// its opponents' ratings from before the day, and teams that did not play
+// only gain rating deviation.
+
+// glickoScale converts between the Glicko and Glicko-2 scales.
+const glickoScale = 173.7178
+
+type GlickoConfig struct {
+	Start    float64 // initial rating
+	StartRD  float64 // initial rating deviation, also the RD ceiling
+	StartVol float64 // initial volatility
+	Tau      float64 // constrains volatility changes; 0.3-1.2 is typical
+	// HomeAdv is added to the home team's rating when computing expected
+	// scores, in rating points.
+	HomeAdv float64
+}
+
+func DefaultGlickoConfig() GlickoConfig {
+	return GlickoConfig{Start: 1500, StartRD: 350, StartVol: 0.06, Tau: 0.5, HomeAdv: 75}
+}
+
+// GlickoRating is a team's rating, rating deviation and volatility on the
+// Glicko scale.
+type GlickoRating struct {
+	Rating float64
+	RD     float64
+	Vol    float64
+}
+
+type glickoGame struct {
+	opp   [2]int
+	adv   float64 // own home edge minus the opponent's, in rating points
+	score float64
+}
+
+// BuildGlicko rates every team-season independently and returns the ratings
+// after the last rating period.
+func BuildGlicko(rows []RegularSeasonCompactRow, cfg GlickoConfig) map[[2]int]GlickoRating {
+	rating := make(map[[2]int]GlickoRating)
+	seasonTeams := make(map[int][][2]int)
+
+	games := sortedGames(rows)
+	for i := 0; i < len(games); {
+		season, day := games[i].Season, games[i].DayNum
+		j := i
+		period := make(map[[2]int][]glickoGame)
+		for ; j < len(games) && games[j].Season == season && games[j].DayNum == day; j++ {
+			g := games[j]
+			w := [2]int{g.Season, g.WTeamID}
+			l := [2]int{g.Season, g.LTeamID}
+			for _, k := range [][2]int{w, l} {
+				if _, ok := rating[k]; !ok {
+					rating[k] = GlickoRating{Rating: cfg.Start, RD: cfg.StartRD, Vol: cfg.StartVol}
+					seasonTeams[season] = append(seasonTeams[season], k)
+				}
+			}
+			hw, hl := homeAdjust(g.WLoc, cfg.HomeAdv)
+			period[w] = append(period[w], glickoGame{opp: l, adv: hw - hl, score: 1})
+			period[l] = append(period[l], glickoGame{opp: w, adv: hl - hw, score: 0})
+		}
+
+		next := make(map[[2]int]GlickoRating, len(seasonTeams[season]))
+		for _, k := range seasonTeams[season] {
+			next[k] = cfg.update(rating[k], period[k], rating)
+		}
+		for k, r := range next {
+			rating[k] = r
+		}
+		i = j
+	}
+	return rating
+}
+
+// update applies one rating period to r; before holds every team's rating at
+// the start of the period.
+func (cfg GlickoConfig) update(r GlickoRating, games []glickoGame, before map[[2]int]GlickoRating) GlickoRating {
+	mu := (r.Rating - cfg.Start) / glickoScale
+	phi := r.RD / glickoScale
+	maxPhi := cfg.StartRD / glickoScale
+
+	if len(games) == 0 {
+		r.RD = math.Min(math.Sqrt(phi*phi+r.Vol*r.Vol), maxPhi) * glickoScale
+		return r
+	}
+
+	var vInv, sum float64
+	for _, g := range games {
+		o := before[g.opp]
+		muJ := (o.Rating - cfg.Start) / glickoScale
+		gJ := glickoG(o.RD / glickoScale)
+		e := 1 / (1 + math.Exp(-gJ*(mu+g.adv/glickoScale-muJ)))
+		vInv += gJ * gJ * e * (1 - e)
+		sum += gJ * (g.score - e)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	vol := cfg.newVolatility(phi, r.Vol, v, delta)
+	phiStar := math.Sqrt(phi*phi + vol*vol)
+	phiNew := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	muNew := mu + phiNew*phiNew*sum
+
+	return GlickoRating{
+		Rating: cfg.Start + glickoScale*muNew,
+		RD:     math.Min(phiNew, maxPhi) * glickoScale,
+		Vol:    vol,
+	}
+}
+
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// newVolatility solves for the new volatility with the Illinois algorithm
+// (step 5 of the Glicko-2 paper).
+func (cfg GlickoConfig) newVolatility(phi, sigma, v, delta float64) float64 {
+	const eps = 1e-6
+	a := math.Log(sigma * sigma)
+	tau2 := cfg.Tau * cfg.Tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-d)/(2*d*d) - (x-a)/tau2
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*cfg.Tau) < 0 {
+			k++
+		}
+		B = a - k*cfg.Tau
+	}
+	fA, fB := f(A), f(B)
+	for i := 0; i < 100 && math.Abs(B-A) > eps; i++ {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+func AttachGlicko(agg map[[2]int]*TeamSeasonAgg, ratings map[[2]int]GlickoRating) {
+	for k, r := range ratings {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.Glicko = r.Rating
+		a.GlickoRD = r.RD
+	}
+}
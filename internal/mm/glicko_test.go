@@ -0,0 +1,26 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGlickoGlickmanExample(t *testing.T) {
+	// The worked example from Glickman's "Example of the Glicko-2 system".
+	cfg := DefaultGlickoConfig()
+	cfg.Tau = 0.5
+	before := map[[2]int]GlickoRating{
+		{2024, 2}: {Rating: 1400, RD: 30, Vol: 0.06},
+		{2024, 3}: {Rating: 1550, RD: 100, Vol: 0.06},
+		{2024, 4}: {Rating: 1700, RD: 300, Vol: 0.06},
+	}
+	games := []glickoGame{
+		{opp: [2]int{2024, 2}, score: 1},
+		{opp: [2]int{2024, 3}, score: 0},
+		{opp: [2]int{2024, 4}, score: 0},
+	}
+	got := cfg.update(GlickoRating{Rating: 1500, RD: 200, Vol: 0.06}, games, before)
+	if math.Abs(got.Rating-1464.06) > 0.01 || math.Abs(got.RD-151.52) > 0.01 || math.Abs(got.Vol-0.05999) > 1e-5 {
+		t.Errorf("update = %+v, want 1464.06 / 151.52 / 0.05999", got)
+	}
+}
@@ -0,0 +1,120 @@
+package mm
+
+import (
+	"sort"
+)
+
+// RatingPoint is a team's rating after its games of DayNum.
+type RatingPoint struct {
+	DayNum int
+	Rating float64
+}
+
+// RatingHistory records each team-season's starting rating and its rating
+// after every day the team played, so ratings can be read at any cutoff day
+// without looking at later games.
+type RatingHistory struct {
+	start  map[[2]int]float64
+	points map[[2]int][]RatingPoint // [season, team] -> points by DayNum
+}
+
+func NewRatingHistory() *RatingHistory {
+	return &RatingHistory{
+		start:  make(map[[2]int]float64),
+		points: make(map[[2]int][]RatingPoint),
+	}
+}
+
+// Begin sets the rating a team enters a season with.
+func (h *RatingHistory) Begin(season, team int, rating float64) {
+	h.start[[2]int{season, team}] = rating
+}
+
+// Record sets a team's rating after its games of day. Days must be recorded
+// in ascending order; a second record for the same day replaces the first.
+func (h *RatingHistory) Record(season, team, day int, rating float64) {
+	k := [2]int{season, team}
+	if _, ok := h.start[k]; !ok {
+		h.start[k] = rating
+	}
+	pts := h.points[k]
+	if n := len(pts); n > 0 && pts[n-1].DayNum == day {
+		pts[n-1].Rating = rating
+		return
+	}
+	h.points[k] = append(pts, RatingPoint{DayNum: day, Rating: rating})
+}
+
+// RatingAsOf returns a team's rating after all its games with DayNum <= day,
+// or its starting rating if it had not played yet. ok is false if the team
+// has no rating in that season.
+func (h *RatingHistory) RatingAsOf(season, team, day int) (float64, bool) {
+	k := [2]int{season, team}
+	start, ok := h.start[k]
+	if !ok {
+		return 0, false
+	}
+	pts := h.points[k]
+	i := sort.Search(len(pts), func(i int) bool { return pts[i].DayNum > day })
+	if i == 0 {
+		return start, true
+	}
+	return pts[i-1].Rating, true
+}
+
+// AsOf returns every team-season's rating as of day.
+func (h *RatingHistory) AsOf(day int) map[[2]int]float64 {
+	out := make(map[[2]int]float64, len(h.start))
+	for k := range h.start {
+		out[k], _ = h.RatingAsOf(k[0], k[1], day)
+	}
+	return out
+}
+
+// End returns every team-season's final rating.
+func (h *RatingHistory) End() map[[2]int]float64 {
+	out := make(map[[2]int]float64, len(h.start))
+	for k, r := range h.start {
+		if pts := h.points[k]; len(pts) > 0 {
+			r = pts[len(pts)-1].Rating
+		}
+		out[k] = r
+	}
+	return out
+}
+
+// Trajectory returns a team's ratings after each day it played in a season.
+func (h *RatingHistory) Trajectory(season, team int) []RatingPoint {
+	return h.points[[2]int{season, team}]
+}
+
+// Keys lists the [season, team] pairs of the history, sorted.
+func (h *RatingHistory) Keys() [][2]int {
+	keys := make([][2]int, 0, len(h.start))
+	for k := range h.start {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// WriteRatingHistoryCSV writes one row per team and day played, preceded by
+// the team's starting rating at DayNum -1.
+func WriteRatingHistoryCSV(path string, h *RatingHistory) error {
+	w, err := NewCSVWriter(path, []string{"Season", "TeamID", "DayNum", "Rating"})
+	if err != nil {
+		return err
+	}
+	for _, k := range h.Keys() {
+		w.WriteRow([]string{fmtInt(k[0]), fmtInt(k[1]), "-1", fmtF(h.start[k])})
+		for _, p := range h.points[k] {
+			w.WriteRow([]string{fmtInt(k[0]), fmtInt(k[1]), fmtInt(p.DayNum), fmtF(p.Rating)})
+		}
+	}
+	return w.Close()
+}
@@ -0,0 +1,63 @@
+package mm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// League tags a row with the competition it came from. Kaggle ships the
+// men's and women's data as separate "M..." and "W..." files whose TeamIDs
+// do not overlap (1xxx vs 3xxx), so rows from both can share one table.
+type League string
+
+const (
+	LeagueMen   League = "M"
+	LeagueWomen League = "W"
+)
+
+// AllLeagues is the default set of leagues loaded by the readers.
+var AllLeagues = []League{LeagueMen, LeagueWomen}
+
+// FileName returns the Kaggle file name for this league, e.g. "MNCAATourneySeeds.csv".
+func (l League) FileName(base string) string {
+	return string(l) + base
+}
+
+// LeagueOfTeam infers the league from the Kaggle TeamID ranges.
+func LeagueOfTeam(teamID int) League {
+	if teamID >= 3000 && teamID < 4000 {
+		return LeagueWomen
+	}
+	return LeagueMen
+}
+
+// ParseLeagues parses a comma separated list such as "M,W".
+func ParseLeagues(s string) ([]League, error) {
+	var out []League
+	seen := map[League]bool{}
+	for _, p := range strings.Split(s, ",") {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		lg := League(p)
+		if lg != LeagueMen && lg != LeagueWomen {
+			return nil, fmt.Errorf("unknown league %q (want M or W)", p)
+		}
+		if !seen[lg] {
+			seen[lg] = true
+			out = append(out, lg)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no leagues in %q", s)
+	}
+	return out, nil
+}
+
+func leaguesOrAll(leagues []League) []League {
+	if len(leagues) == 0 {
+		return AllLeagues
+	}
+	return leagues
+}
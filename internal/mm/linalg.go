@@ -0,0 +1,140 @@
+package mm
+
+import (
+	"fmt"
+	"math"
+)
+
+// matrix is a dense row-major square matrix, sized for one season's teams.
+type matrix struct {
+	n int
+	a []float64
+}
+
+func newMatrix(n int) *matrix {
+	return &matrix{n: n, a: make([]float64, n*n)}
+}
+
+func (m *matrix) at(i, j int) float64     { return m.a[i*m.n+j] }
+func (m *matrix) add(i, j int, v float64) { m.a[i*m.n+j] += v }
+
+// addDiag adds v to every diagonal entry.
+func (m *matrix) addDiag(v float64) {
+	for i := 0; i < m.n; i++ {
+		m.a[i*m.n+i] += v
+	}
+}
+
+// cholesky holds the lower-triangular factor L of a symmetric positive
+// definite matrix A = L·Lᵀ.
+type cholesky struct {
+	n int
+	l []float64
+}
+
+func factorCholesky(m *matrix) (*cholesky, error) {
+	n := m.n
+	l := make([]float64, n*n)
+	for j := 0; j < n; j++ {
+		d := m.at(j, j)
+		for k := 0; k < j; k++ {
+			d -= l[j*n+k] * l[j*n+k]
+		}
+		if d <= 0 || math.IsNaN(d) {
+			return nil, fmt.Errorf("matrix is not positive definite (pivot %d)", j)
+		}
+		d = math.Sqrt(d)
+		l[j*n+j] = d
+		for i := j + 1; i < n; i++ {
+			s := m.at(i, j)
+			for k := 0; k < j; k++ {
+				s -= l[i*n+k] * l[j*n+k]
+			}
+			l[i*n+j] = s / d
+		}
+	}
+	return &cholesky{n: n, l: l}, nil
+}
+
+// solve returns x with A·x = b.
+func (c *cholesky) solve(b []float64) []float64 {
+	n := c.n
+	x := append([]float64{}, b...)
+	for i := 0; i < n; i++ {
+		s := x[i]
+		for k := 0; k < i; k++ {
+			s -= c.l[i*n+k] * x[k]
+		}
+		x[i] = s / c.l[i*n+i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		s := x[i]
+		for k := i + 1; k < n; k++ {
+			s -= c.l[k*n+i] * x[k]
+		}
+		x[i] = s / c.l[i*n+i]
+	}
+	return x
+}
+
+func solveSPD(m *matrix, b []float64) ([]float64, error) {
+	c, err := factorCholesky(m)
+	if err != nil {
+		return nil, err
+	}
+	return c.solve(b), nil
+}
+
+// inverseColumn writes column j of L⁻¹ into col. The column is zero above
+// the diagonal.
+func (c *cholesky) inverseColumn(j int, col []float64) {
+	n := c.n
+	for i := 0; i < j; i++ {
+		col[i] = 0
+	}
+	col[j] = 1 / c.l[j*n+j]
+	for i := j + 1; i < n; i++ {
+		s := 0.0
+		for k := j; k < i; k++ {
+			s -= c.l[i*n+k] * col[k]
+		}
+		col[i] = s / c.l[i*n+i]
+	}
+}
+
+// inverseDiag returns the diagonal of A⁻¹, using A⁻¹ = L⁻ᵀ·L⁻¹ so that
+// (A⁻¹)ii is the squared norm of column i of L⁻¹.
+func (c *cholesky) inverseDiag() []float64 {
+	n := c.n
+	out := make([]float64, n)
+	col := make([]float64, n)
+	for j := 0; j < n; j++ {
+		c.inverseColumn(j, col)
+		sum := 0.0
+		for i := j; i < n; i++ {
+			sum += col[i] * col[i]
+		}
+		out[j] = sum
+	}
+	return out
+}
+
+// inverseAt returns (A⁻¹)ij for each pair. L⁻¹ is formed once and each entry
+// is the dot product of columns i and j.
+func (c *cholesky) inverseAt(pairs [][2]int) []float64 {
+	n := c.n
+	cols := make([]float64, n*n)
+	for j := 0; j < n; j++ {
+		c.inverseColumn(j, cols[j*n:(j+1)*n])
+	}
+	out := make([]float64, len(pairs))
+	for p, ij := range pairs {
+		ci, cj := cols[ij[0]*n:(ij[0]+1)*n], cols[ij[1]*n:(ij[1]+1)*n]
+		sum := 0.0
+		for k := max(ij[0], ij[1]); k < n; k++ {
+			sum += ci[k] * cj[k]
+		}
+		out[p] = sum
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+// spd3 is A = L·Lᵀ with L = [2 0 0; 1 2 0; 0 1 2], so det A = 64 and
+// A⁻¹ = [21 -10 4; -10 20 -8; 4 -8 16] / 64.
+func spd3() *matrix {
+	m := newMatrix(3)
+	for i, v := range []float64{4, 2, 0, 2, 5, 2, 0, 2, 5} {
+		m.add(i/3, i%3, v)
+	}
+	return m
+}
+
+func TestCholesky(t *testing.T) {
+	c, err := factorCholesky(spd3())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantL := []float64{2, 0, 0, 1, 2, 0, 0, 1, 2}
+	for i, v := range wantL {
+		if math.Abs(c.l[i]-v) > 1e-12 {
+			t.Fatalf("L = %v, want %v", c.l, wantL)
+		}
+	}
+
+	x := c.solve([]float64{8, 18, 19})
+	for i, v := range []float64{1, 2, 3} {
+		if math.Abs(x[i]-v) > 1e-12 {
+			t.Errorf("solve = %v, want [1 2 3]", x)
+			break
+		}
+	}
+
+	inv := [3][3]float64{{21, -10, 4}, {-10, 20, -8}, {4, -8, 16}}
+	diag := c.inverseDiag()
+	var pairs [][2]int
+	for i := 0; i < 3; i++ {
+		if math.Abs(diag[i]-inv[i][i]/64) > 1e-12 {
+			t.Errorf("inverseDiag()[%d] = %v, want %v", i, diag[i], inv[i][i]/64)
+		}
+		for j := 0; j < 3; j++ {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	for p, v := range c.inverseAt(pairs) {
+		i, j := pairs[p][0], pairs[p][1]
+		if math.Abs(v-inv[i][j]/64) > 1e-12 {
+			t.Errorf("inverseAt(%d, %d) = %v, want %v", i, j, v, inv[i][j]/64)
+		}
+	}
+}
+
+func TestCholeskyNotPositiveDefinite(t *testing.T) {
+	m := newMatrix(2)
+	m.add(0, 0, 1)
+	m.add(0, 1, 2)
+	m.add(1, 0, 2)
+	m.add(1, 1, 1)
+	if _, err := factorCholesky(m); err == nil {
+		t.Error("factorCholesky accepted an indefinite matrix")
+	}
+}
@@ -8,9 +8,15 @@ import (
 	"path/filepath"
 )
 
+// LogRegModel is a logistic regression on standardized features: each input
+// has Mean subtracted and is divided by Std before the weights apply. Models
+// saved without Mean and Std take the raw features. Missing (NaN) inputs
+// count as the mean.
 type LogRegModel struct {
 	FeatureNames []string  `json:"feature_names"`
 	Weights      []float64 `json:"weights"` // bias at index 0
+	Mean         []float64 `json:"mean,omitempty"`
+	Std          []float64 `json:"std,omitempty"`
 }
 
 func sigmoid(x float64) float64 {
@@ -24,11 +30,61 @@ func sigmoid(x float64) float64 {
 }
 
 func (m *LogRegModel) PredictProba(x []float64) float64 {
-	z := m.Weights[0]
-	for i := 0; i < len(x) && i+1 < len(m.Weights); i++ {
-		z += m.Weights[i+1] * x[i]
+	return sigmoid(dotBias(m.Weights, m.standardize(x)))
+}
+
+func (m *LogRegModel) standardize(x []float64) []float64 {
+	out := make([]float64, len(x))
+	for i, v := range x {
+		switch {
+		case math.IsNaN(v):
+			out[i] = 0
+		case m.Mean == nil:
+			out[i] = v
+		default:
+			out[i] = (v - m.Mean[i]) / m.Std[i]
+		}
+	}
+	return out
+}
+
+// featureScale returns the mean and standard deviation of every column of X,
+// skipping missing (NaN) values. Constant columns get a standard deviation of
+// 1 so they stay finite.
+func featureScale(X [][]float64, d int) (mean, std []float64) {
+	mean = make([]float64, d)
+	std = make([]float64, d)
+	n := make([]float64, d)
+	for _, x := range X {
+		for j := 0; j < d; j++ {
+			if !math.IsNaN(x[j]) {
+				mean[j] += x[j]
+				n[j]++
+			}
+		}
+	}
+	for j := range mean {
+		if n[j] > 0 {
+			mean[j] /= n[j]
+		}
 	}
-	return sigmoid(z)
+	for _, x := range X {
+		for j := 0; j < d; j++ {
+			if !math.IsNaN(x[j]) {
+				dv := x[j] - mean[j]
+				std[j] += dv * dv
+			}
+		}
+	}
+	for j := range std {
+		if n[j] > 0 {
+			std[j] = math.Sqrt(std[j] / n[j])
+		}
+		if std[j] < 1e-12 {
+			std[j] = 1
+		}
+	}
+	return mean, std
 }
 
 type TrainConfig struct {
@@ -47,17 +103,23 @@ func TrainLogReg(X [][]float64, y []float64, featureNames []string, cfg TrainCon
 	}
 	d := len(X[0])
 	w := make([]float64, d+1)
+	model := &LogRegModel{FeatureNames: featureNames}
+	model.Mean, model.Std = featureScale(X, d)
+	Z := make([][]float64, len(X))
+	for i := range X {
+		Z[i] = model.standardize(X[i])
+	}
 
 	n := float64(len(X))
 	for epoch := 0; epoch < cfg.Epochs; epoch++ {
 		grad := make([]float64, d+1)
 
 		for i := 0; i < len(X); i++ {
-			p := sigmoid(dotBias(w, X[i]))
+			p := sigmoid(dotBias(w, Z[i]))
 			err := p - y[i]
 			grad[0] += err
 			for j := 0; j < d; j++ {
-				grad[j+1] += err * X[i][j]
+				grad[j+1] += err * Z[i][j]
 			}
 		}
 
@@ -71,7 +133,8 @@ func TrainLogReg(X [][]float64, y []float64, featureNames []string, cfg TrainCon
 		}
 	}
 
-	return &LogRegModel{FeatureNames: featureNames, Weights: w}
+	model.Weights = w
+	return model
 }
 
 func dotBias(w []float64, x []float64) float64 {
@@ -105,5 +168,8 @@ func LoadModelJSON(path string) (*LogRegModel, error) {
 	if len(m.Weights) != 1+len(m.FeatureNames) {
 		return nil, fmt.Errorf("bad model: weights=%d features=%d", len(m.Weights), len(m.FeatureNames))
 	}
+	if m.Mean != nil && (len(m.Mean) != len(m.FeatureNames) || len(m.Std) != len(m.FeatureNames)) {
+		return nil, fmt.Errorf("bad model: mean=%d std=%d features=%d", len(m.Mean), len(m.Std), len(m.FeatureNames))
+	}
 	return &m, nil
 }
@@ -0,0 +1,35 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrainLogRegMissingInputs(t *testing.T) {
+	// The first column separates the classes; the second is noise and
+	// missing on half the rows.
+	var X [][]float64
+	var y []float64
+	for i := 0; i < 200; i++ {
+		label := float64(i % 2)
+		noise := float64(i%7) - 3
+		if i%4 < 2 {
+			noise = math.NaN()
+		}
+		X = append(X, []float64{400 * (label - 0.5), noise})
+		y = append(y, label)
+	}
+	m := TrainLogReg(X, y, []string{"gap", "noise"}, DefaultTrainConfig())
+
+	if m.Mean[0] != 0 || math.Abs(m.Std[0]-200) > 1e-9 {
+		t.Errorf("gap scaled by mean=%v std=%v, want 0 and 200", m.Mean[0], m.Std[0])
+	}
+	for _, x := range [][]float64{{200, math.NaN()}, {200, 1}} {
+		if p := m.PredictProba(x); math.IsNaN(p) || p < 0.9 {
+			t.Errorf("PredictProba(%v) = %v, want > 0.9", x, p)
+		}
+	}
+	if p := m.PredictProba([]float64{-200, math.NaN()}); p > 0.1 {
+		t.Errorf("PredictProba(-200) = %v, want < 0.1", p)
+	}
+}
@@ -0,0 +1,101 @@
+package mm
+
+import "math"
+
+// LRMC-style Markov chain ratings (Kvam and Sokol 2006). A voter sitting on a
+// team picks one of its games at random and moves to the opponent with the
+// probability that the opponent is the better team, judged from the
+// home-adjusted margin. The stationary distribution of the walk, damped as in
+// PageRank so it always exists, is the rating.
+
+type LRMCConfig struct {
+	// Scale is the margin, in points, at which the winner is judged better
+	// with probability 1/(1+e^-1) ≈ 0.73.
+	Scale float64
+	// HomeAdv is subtracted from the home team's margin, in points.
+	HomeAdv float64
+	// Damping is the probability of following a game rather than jumping to
+	// a random team.
+	Damping float64
+}
+
+func DefaultLRMCConfig() LRMCConfig {
+	return LRMCConfig{Scale: 8, HomeAdv: 3.5, Damping: 0.85}
+}
+
+// BuildLRMC rates every team-season in rows. Ratings are scaled so the
+// average team of a season and league is 1.
+func BuildLRMC(rows []RegularSeasonCompactRow, cfg LRMCConfig) map[[2]int]float64 {
+	out := make(map[[2]int]float64)
+	for _, sg := range groupSeasons(rows) {
+		pi := stationaryLRMC(sg, cfg)
+		n := float64(len(sg.Teams))
+		for i, t := range sg.Teams {
+			out[[2]int{sg.Season, t}] = pi[i] * n
+		}
+	}
+	return out
+}
+
+func stationaryLRMC(sg *seasonGroup, cfg LRMCConfig) []float64 {
+	n := len(sg.Teams)
+	type edge struct {
+		to int
+		p  float64
+	}
+	out := make([][]edge, n)
+	stay := make([]float64, n)
+	games := make([]float64, n)
+	for _, g := range sg.Games {
+		w, l := sg.index[g.WTeamID], sg.index[g.LTeamID]
+		margin := float64(g.WScore-g.LScore) - homeSign(g.WLoc)*cfg.HomeAdv
+		s := 1 / (1 + math.Exp(-margin/cfg.Scale)) // P(winner is better)
+		out[l] = append(out[l], edge{w, s})
+		stay[l] += 1 - s
+		out[w] = append(out[w], edge{l, 1 - s})
+		stay[w] += s
+		games[w]++
+		games[l]++
+	}
+
+	pi := make([]float64, n)
+	for i := range pi {
+		pi[i] = 1 / float64(n)
+	}
+	next := make([]float64, n)
+	for iter := 0; iter < 1000; iter++ {
+		for i := range next {
+			next[i] = (1 - cfg.Damping) / float64(n)
+		}
+		for i := 0; i < n; i++ {
+			if games[i] == 0 {
+				continue
+			}
+			m := cfg.Damping * pi[i] / games[i]
+			next[i] += m * stay[i]
+			for _, e := range out[i] {
+				next[e.to] += m * e.p
+			}
+		}
+		diff := 0.0
+		for i := range pi {
+			diff += math.Abs(next[i] - pi[i])
+		}
+		pi, next = next, pi
+		if diff < 1e-12 {
+			break
+		}
+	}
+	return pi
+}
+
+func AttachLRMC(agg map[[2]int]*TeamSeasonAgg, ratings map[[2]int]float64) {
+	for k, r := range ratings {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.LRMC = r
+	}
+}
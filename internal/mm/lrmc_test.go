@@ -0,0 +1,35 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLRMC(t *testing.T) {
+	cfg := DefaultLRMCConfig()
+
+	// Equal margins around a cycle leave every team at the average.
+	cycle := BuildLRMC([]RegularSeasonCompactRow{
+		neutralGame(1, 70, 2, 60),
+		neutralGame(2, 70, 3, 60),
+		neutralGame(3, 70, 1, 60),
+	}, cfg)
+	for team := 1; team <= 3; team++ {
+		if r := cycle[[2]int{2024, team}]; math.Abs(r-1) > 1e-9 {
+			t.Errorf("cycle team %d rating %v, want 1", team, r)
+		}
+	}
+
+	got := BuildLRMC([]RegularSeasonCompactRow{
+		neutralGame(1, 80, 2, 60),
+		neutralGame(1, 80, 3, 60),
+		neutralGame(2, 70, 3, 65),
+	}, cfg)
+	a, b, c := got[[2]int{2024, 1}], got[[2]int{2024, 2}], got[[2]int{2024, 3}]
+	if math.Abs(a+b+c-3) > 1e-9 {
+		t.Errorf("ratings average %v, want 1", (a+b+c)/3)
+	}
+	if !(a > b && b > c) {
+		t.Errorf("ratings %v, %v, %v not ordered by results", a, b, c)
+	}
+}
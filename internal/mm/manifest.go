@@ -0,0 +1,293 @@
+package mm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ManifestFile describes one Kaggle file of a data release. Season and day
+// ranges are zero when the file has no such column.
+type ManifestFile struct {
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+	Rows      int    `json:"rows"`
+	MinSeason int    `json:"min_season,omitempty"`
+	MaxSeason int    `json:"max_season,omitempty"`
+	MinDayNum int    `json:"min_day_num,omitempty"`
+	MaxDayNum int    `json:"max_day_num,omitempty"`
+}
+
+// Manifest pins down the exact data release a run used.
+type Manifest struct {
+	Source  string         `json:"source"`
+	Created time.Time      `json:"created"`
+	Files   []ManifestFile `json:"files"`
+}
+
+// ManifestDiff is one difference between two manifests.
+type ManifestDiff struct {
+	Name   string
+	Kind   string // "missing", "extra" or "changed"
+	Detail string
+}
+
+func (d ManifestDiff) String() string {
+	if d.Detail == "" {
+		return fmt.Sprintf("%-8s %s", d.Kind, d.Name)
+	}
+	return fmt.Sprintf("%-8s %s: %s", d.Kind, d.Name, d.Detail)
+}
+
+func isDataFile(name string) bool {
+	n := strings.ToLower(name)
+	return strings.HasSuffix(n, ".csv") || strings.HasSuffix(n, ".csv"+gzipExt)
+}
+
+// BuildManifest hashes and scans every CSV in the loader's source. Files the
+// loader already hashed for the snapshot cache are not hashed again.
+func BuildManifest(l *Loader) (*Manifest, error) {
+	members, err := l.Files()
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{Source: l.DataDir, Created: time.Now().UTC()}
+	for _, name := range members {
+		if !isDataFile(name) {
+			continue
+		}
+		mf, err := scanManifestFile(l, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", l.displayPath(name), err)
+		}
+		m.Files = append(m.Files, mf)
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Name < m.Files[j].Name })
+	return m, nil
+}
+
+// scanManifestFile hashes the raw member bytes, unless the loader has a stamp
+// for the member, and in the same pass counts the rows and the Season/DayNum
+// ranges of the decompressed CSV. New hashes are recorded as stamps. It only
+// splits lines and never tokenizes the CSV, so it is cheap and does not fail
+// on rows that the readers would drop in lenient mode; rows are counted as
+// non-empty lines, and lines with quotes are left out of the ranges.
+func scanManifestFile(l *Loader, name string) (ManifestFile, error) {
+	fsys, err := l.source()
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return ManifestFile{}, err
+	}
+
+	stamp, stamped := l.stamps[name]
+	h := sha256.New()
+	var src io.Reader = f
+	if !stamped {
+		src = io.TeeReader(f, h)
+	}
+	if strings.EqualFold(path.Ext(name), gzipExt) {
+		zr, err := gzip.NewReader(src)
+		if err != nil {
+			return ManifestFile{}, err
+		}
+		defer zr.Close()
+		src = zr
+	}
+
+	mf := ManifestFile{Name: path.Base(name), Size: st.Size()}
+	br := bufio.NewReaderSize(src, 64<<10)
+	seasonCol, dayCol := -1, -1
+	header := true
+	var seenSeason, seenDay bool
+	for {
+		line, err := br.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			// An overlong line still counts as a row but is not ranged.
+			for err == bufio.ErrBufferFull {
+				_, err = br.ReadSlice('\n')
+			}
+			if header {
+				header = false
+			} else {
+				mf.Rows++
+			}
+			line = nil
+		}
+		if err != nil && err != io.EOF {
+			return ManifestFile{}, err
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			if header {
+				seasonCol, dayCol = manifestColumns(line)
+				header = false
+			} else {
+				mf.Rows++
+				if v, ok := plainIntField(line, seasonCol); ok {
+					mf.MinSeason, mf.MaxSeason = widen(mf.MinSeason, mf.MaxSeason, v, !seenSeason)
+					seenSeason = true
+				}
+				if v, ok := plainIntField(line, dayCol); ok {
+					mf.MinDayNum, mf.MaxDayNum = widen(mf.MinDayNum, mf.MaxDayNum, v, !seenDay)
+					seenDay = true
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if stamped {
+		mf.Size, mf.SHA256 = stamp.size, stamp.sha256
+		return mf, nil
+	}
+	// Drain anything the decompressor left so the hash covers the whole file.
+	if _, err := io.Copy(io.Discard, src); err != nil {
+		return ManifestFile{}, err
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		return ManifestFile{}, err
+	}
+	mf.SHA256 = hex.EncodeToString(h.Sum(nil))
+	l.recordStamp(name, sourceStamp{size: st.Size(), modTime: st.ModTime().UTC(), sha256: mf.SHA256})
+	return mf, nil
+}
+
+// manifestColumns finds the Season and DayNum (or RankingDayNum) columns of a
+// header line, or -1.
+func manifestColumns(line []byte) (season, day int) {
+	season, day = -1, -1
+	ranking := -1
+	for i, name := range strings.Split(string(line), ",") {
+		switch strings.Trim(name, "\"\ufeff \t\r\n") {
+		case "Season":
+			season = i
+		case "DayNum":
+			day = i
+		case "RankingDayNum":
+			ranking = i
+		}
+	}
+	if day < 0 {
+		day = ranking
+	}
+	return season, day
+}
+
+// plainIntField parses field i of an unquoted CSV line as an integer.
+func plainIntField(line []byte, i int) (int, bool) {
+	if i < 0 || bytes.IndexByte(line, '"') >= 0 {
+		return 0, false
+	}
+	for ; i > 0; i-- {
+		j := bytes.IndexByte(line, ',')
+		if j < 0 {
+			return 0, false
+		}
+		line = line[j+1:]
+	}
+	if j := bytes.IndexByte(line, ','); j >= 0 {
+		line = line[:j]
+	}
+	v, err := strconv.Atoi(string(bytes.TrimSpace(line)))
+	return v, err == nil
+}
+
+func widen(lo, hi, v int, first bool) (int, int) {
+	if first || v < lo {
+		lo = v
+	}
+	if first || v > hi {
+		hi = v
+	}
+	return lo, hi
+}
+
+// CompareManifests lists the files that are missing from, added to or
+// different in got relative to want.
+func CompareManifests(want, got *Manifest) []ManifestDiff {
+	gotByName := make(map[string]ManifestFile, len(got.Files))
+	for _, f := range got.Files {
+		gotByName[f.Name] = f
+	}
+	wantNames := make(map[string]bool, len(want.Files))
+
+	var out []ManifestDiff
+	for _, w := range want.Files {
+		wantNames[w.Name] = true
+		g, ok := gotByName[w.Name]
+		if !ok {
+			out = append(out, ManifestDiff{Name: w.Name, Kind: "missing"})
+			continue
+		}
+		if w.SHA256 == g.SHA256 {
+			continue
+		}
+		var parts []string
+		if w.Size != g.Size {
+			parts = append(parts, fmt.Sprintf("size %d -> %d", w.Size, g.Size))
+		}
+		if w.Rows != g.Rows {
+			parts = append(parts, fmt.Sprintf("rows %d -> %d", w.Rows, g.Rows))
+		}
+		if w.MinSeason != g.MinSeason || w.MaxSeason != g.MaxSeason {
+			parts = append(parts, fmt.Sprintf("seasons %d-%d -> %d-%d", w.MinSeason, w.MaxSeason, g.MinSeason, g.MaxSeason))
+		}
+		if w.MinDayNum != g.MinDayNum || w.MaxDayNum != g.MaxDayNum {
+			parts = append(parts, fmt.Sprintf("days %d-%d -> %d-%d", w.MinDayNum, w.MaxDayNum, g.MinDayNum, g.MaxDayNum))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, "sha256 differs")
+		}
+		out = append(out, ManifestDiff{Name: w.Name, Kind: "changed", Detail: strings.Join(parts, ", ")})
+	}
+	for _, g := range got.Files {
+		if !wantNames[g.Name] {
+			out = append(out, ManifestDiff{Name: g.Name, Kind: "extra"})
+		}
+	}
+	return out
+}
+
+func SaveManifestJSON(path string, m *Manifest) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+func LoadManifestJSON(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,56 @@
+package mm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestReusesStamps(t *testing.T) {
+	dir := t.TempDir()
+	csv := "Season,Seed,TeamID\n2023,W01,1101\n2024,X16a,1102\n"
+	if err := os.WriteFile(filepath.Join(dir, "MNCAATourneySeeds.csv"), []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(csv))
+	want := hex.EncodeToString(sum[:])
+
+	l := NewLoader(dir)
+	defer l.Close()
+	l.CacheDir = filepath.Join(dir, "cache")
+	if _, err := l.Seeds(LeagueMen); err != nil {
+		t.Fatal(err)
+	}
+	s, ok := l.stamps["MNCAATourneySeeds.csv"]
+	if !ok || s.sha256 != want {
+		t.Fatalf("stamp after reading = %+v, want sha256 %s", s, want)
+	}
+
+	// A recorded stamp is trusted rather than recomputed.
+	s.sha256 = "recorded"
+	l.stamps["MNCAATourneySeeds.csv"] = s
+	m, err := BuildManifest(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 1 {
+		t.Fatalf("manifest files %+v", m.Files)
+	}
+	f := m.Files[0]
+	if f.SHA256 != "recorded" || f.Size != int64(len(csv)) || f.Rows != 2 || f.MinSeason != 2023 || f.MaxSeason != 2024 {
+		t.Errorf("manifest entry %+v", f)
+	}
+
+	// Without a stamp the manifest hashes the file and records the stamp.
+	fresh := NewLoader(dir)
+	defer fresh.Close()
+	m, err = BuildManifest(fresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Files[0].SHA256 != want || fresh.stamps["MNCAATourneySeeds.csv"].sha256 != want {
+		t.Errorf("manifest sha256 %s, stamp %+v, want %s", m.Files[0].SHA256, fresh.stamps, want)
+	}
+}
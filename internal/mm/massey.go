@@ -0,0 +1,214 @@
+package mm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Least-squares Massey ratings (Massey 1997) fitted per season and league:
+// every game asks rating(winner) - rating(loser) (+ home term) to equal the
+// margin, or 1 in the win/loss variant. Ratings are centred on zero.
+
+type MasseyConfig struct {
+	// WinLoss fits wins instead of point margins.
+	WinLoss bool
+	// MarginCap limits the margin a single game can contribute; 0 disables.
+	MarginCap int
+	// FitHome estimates a home-court term jointly with the ratings.
+	FitHome bool
+}
+
+func DefaultMasseyConfig() MasseyConfig {
+	return MasseyConfig{MarginCap: 25, FitHome: true}
+}
+
+// MasseyRating splits a rating into offense and defense with Rating = Off +
+// Def, as in Massey's method; Off and Def are zero in the win/loss variant.
+type MasseyRating struct {
+	Rating float64
+	Off    float64
+	Def    float64
+}
+
+// masseyRidge keeps the normal equations positive definite when the game
+// graph is not yet connected (early cutoffs).
+const masseyRidge = 1e-6
+
+// seasonGroup is the games of one season and league, with teams numbered
+// densely in TeamID order.
+type seasonGroup struct {
+	Season int
+	League League
+	Teams  []int
+	index  map[int]int
+	Games  []RegularSeasonCompactRow
+}
+
+// groupSeasons splits games by season and league; pass GamesThrough output to
+// rate as of a cutoff day.
+func groupSeasons(rows []RegularSeasonCompactRow) []*seasonGroup {
+	byKey := map[seasonKey]*seasonGroup{}
+	var out []*seasonGroup
+	for _, g := range rows {
+		k := seasonKey{League: LeagueOfTeam(g.WTeamID), Season: g.Season}
+		sg, ok := byKey[k]
+		if !ok {
+			sg = &seasonGroup{Season: k.Season, League: k.League, index: map[int]int{}}
+			byKey[k] = sg
+			out = append(out, sg)
+		}
+		sg.Games = append(sg.Games, g)
+		for _, t := range []int{g.WTeamID, g.LTeamID} {
+			if _, ok := sg.index[t]; !ok {
+				sg.index[t] = -1
+				sg.Teams = append(sg.Teams, t)
+			}
+		}
+	}
+	for _, sg := range out {
+		sort.Ints(sg.Teams)
+		for i, t := range sg.Teams {
+			sg.index[t] = i
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Season != out[j].Season {
+			return out[i].Season < out[j].Season
+		}
+		return out[i].League < out[j].League
+	})
+	return out
+}
+
+// homeSign is +1 when the winner was at home, -1 when the loser was and 0
+// on a neutral court.
+func homeSign(wLoc string) float64 {
+	switch wLoc {
+	case "H":
+		return 1
+	case "A":
+		return -1
+	}
+	return 0
+}
+
+// BuildMasseyRatings fits Massey ratings for every team-season in rows.
+func BuildMasseyRatings(rows []RegularSeasonCompactRow, cfg MasseyConfig) (map[[2]int]MasseyRating, error) {
+	out := make(map[[2]int]MasseyRating)
+	for _, sg := range groupSeasons(rows) {
+		r, err := fitMassey(sg, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("massey %s %d: %w", sg.League, sg.Season, err)
+		}
+		for i, t := range sg.Teams {
+			out[[2]int{sg.Season, t}] = r[i]
+		}
+	}
+	return out, nil
+}
+
+func fitMassey(sg *seasonGroup, cfg MasseyConfig) ([]MasseyRating, error) {
+	n := len(sg.Teams)
+	dim := n
+	if cfg.FitHome {
+		dim++ // last unknown is the home term
+	}
+	m := newMatrix(dim)
+	p := make([]float64, dim)
+	for _, g := range sg.Games {
+		w, l := sg.index[g.WTeamID], sg.index[g.LTeamID]
+		y := 1.0
+		if !cfg.WinLoss {
+			margin := g.WScore - g.LScore
+			if cfg.MarginCap > 0 && margin > cfg.MarginCap {
+				margin = cfg.MarginCap
+			}
+			y = float64(margin)
+		}
+		m.add(w, w, 1)
+		m.add(l, l, 1)
+		m.add(w, l, -1)
+		m.add(l, w, -1)
+		p[w] += y
+		p[l] -= y
+		if h := homeSign(g.WLoc); cfg.FitHome && h != 0 {
+			m.add(n, n, 1)
+			m.add(w, n, h)
+			m.add(n, w, h)
+			m.add(l, n, -h)
+			m.add(n, l, -h)
+			p[n] += h * y
+		}
+	}
+	// Adding 1 to every team entry pins the ratings to sum to zero.
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.add(i, j, 1)
+		}
+	}
+	m.addDiag(masseyRidge)
+	x, err := solveSPD(m, p)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MasseyRating, n)
+	for i := range out {
+		out[i].Rating = x[i]
+	}
+	if cfg.WinLoss {
+		return out, nil
+	}
+	home := 0.0
+	if cfg.FitHome {
+		home = x[n]
+	}
+	def, err := masseyDefense(sg, x[:n], home)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Def = def[i]
+		out[i].Off = out[i].Rating - def[i]
+	}
+	return out, nil
+}
+
+// masseyDefense solves (T + P)·d = T·r - f, where T holds games played, P
+// the games between each pair and f the points scored with half the home
+// term removed from each side.
+func masseyDefense(sg *seasonGroup, r []float64, home float64) ([]float64, error) {
+	n := len(sg.Teams)
+	m := newMatrix(n)
+	f := make([]float64, n)
+	for _, g := range sg.Games {
+		w, l := sg.index[g.WTeamID], sg.index[g.LTeamID]
+		m.add(w, w, 1)
+		m.add(l, l, 1)
+		m.add(w, l, 1)
+		m.add(l, w, 1)
+		h := homeSign(g.WLoc) * home / 2
+		f[w] += float64(g.WScore) - h
+		f[l] += float64(g.LScore) + h
+	}
+	b := make([]float64, n)
+	for i := range b {
+		// A team never plays itself, so the diagonal of T + P is T.
+		b[i] = m.at(i, i)*r[i] - f[i]
+	}
+	m.addDiag(masseyRidge)
+	return solveSPD(m, b)
+}
+
+func AttachMasseyRatings(agg map[[2]int]*TeamSeasonAgg, ratings map[[2]int]MasseyRating) {
+	for k, r := range ratings {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.MasseyRating = r.Rating
+		a.MasseyOff = r.Off
+		a.MasseyDef = r.Def
+	}
+}
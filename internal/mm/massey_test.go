@@ -0,0 +1,39 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+// neutralGame is a neutral-court game of the 2024 season.
+func neutralGame(w, ws, l, ls int) RegularSeasonCompactRow {
+	return RegularSeasonCompactRow{Season: 2024, DayNum: 10, WTeamID: w, WScore: ws, LTeamID: l, LScore: ls, WLoc: "N"}
+}
+
+func TestMasseyRoundRobin(t *testing.T) {
+	// Margins are consistent, so the fit is exact: A - B = 10, B - C = 5.
+	rows := []RegularSeasonCompactRow{
+		neutralGame(1, 70, 2, 60),
+		neutralGame(2, 65, 3, 60),
+		neutralGame(1, 80, 3, 65),
+	}
+	got, err := BuildMasseyRatings(rows, MasseyConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]float64{1: 25.0 / 3, 2: -5.0 / 3, 3: -20.0 / 3}
+	sum := 0.0
+	for team, w := range want {
+		r := got[[2]int{2024, team}]
+		sum += r.Rating
+		if math.Abs(r.Rating-w) > 1e-4 {
+			t.Errorf("team %d rating %v, want %v", team, r.Rating, w)
+		}
+		if math.Abs(r.Off+r.Def-r.Rating) > 1e-9 {
+			t.Errorf("team %d Off %v + Def %v != Rating %v", team, r.Off, r.Def, r.Rating)
+		}
+	}
+	if math.Abs(sum) > 1e-9 {
+		t.Errorf("ratings sum to %v, want 0", sum)
+	}
+}
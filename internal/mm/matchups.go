@@ -59,6 +59,8 @@ func BuildTestMatchupsFromIDs(ids []string) ([]MatchupFeatureRow, error) {
 	return out, nil
 }
 
+// JoinFeatures fills every matchup's feature vector with the registered
+// matchup features, in MatchupFeatureNames order.
 func JoinFeatures(matchups []MatchupFeatureRow, agg map[[2]int]*TeamSeasonAgg) []MatchupFeatureRow {
 	get := func(season, team int) *TeamSeasonAgg {
 		a, ok := agg[[2]int{season, team}]
@@ -68,23 +70,25 @@ func JoinFeatures(matchups []MatchupFeatureRow, agg map[[2]int]*TeamSeasonAgg) [
 		return a
 	}
 
+	features := MatchupFeatures()
 	for i := range matchups {
 		m := &matchups[i]
 		a := get(m.Season, m.TeamA)
 		b := get(m.Season, m.TeamB)
 
-		m.DSeed = a.Seed - b.Seed
-		m.DElo = a.EloEnd - b.EloEnd
-		m.DWinPct = a.WinPct - b.WinPct
-		m.DAvgMargin = a.AvgMargin - b.AvgMargin
-		m.DAvgPF = a.AvgPF - b.AvgPF
-		m.DAvgPA = a.AvgPA - b.AvgPA
-		m.DMasseyOrd = a.MasseyOrdinal - b.MasseyOrdinal
+		m.Features = make([]float64, len(features))
+		for j, f := range features {
+			m.Features[j] = f.Value(a, b)
+		}
 	}
 	return matchups
 }
 
-func WriteMatchupsCSV(outDir, name string, rows []MatchupFeatureRow) (string, error) {
+// matchupKeyCols are the non-feature columns of a matchup table.
+var matchupKeyCols = []string{"ID", "Season", "TeamA", "TeamB"}
+
+// WriteMatchupsCSV writes the rows with one column per feature name.
+func WriteMatchupsCSV(outDir, name string, features []string, rows []MatchupFeatureRow) (string, error) {
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return "", err
 	}
@@ -97,30 +101,24 @@ func WriteMatchupsCSV(outDir, name string, rows []MatchupFeatureRow) (string, er
 	w := csv.NewWriter(f)
 	defer w.Flush()
 
-	header := []string{
-		"ID", "Season", "TeamA", "TeamB",
-		"DSeed", "DElo", "DWinPct", "DAvgMargin", "DAvgPF", "DAvgPA", "DMasseyOrd",
-		"Label", "HasLabel",
-	}
+	header := append(append(append([]string{}, matchupKeyCols...), features...), "Label", "HasLabel")
 	if err := w.Write(header); err != nil {
 		return "", err
 	}
 	for _, r := range rows {
+		if len(r.Features) != len(features) {
+			return "", fmt.Errorf("%s: row %s has %d features, want %d", path, r.ID, len(r.Features), len(features))
+		}
 		rec := []string{
 			r.ID,
 			strconv.Itoa(r.Season),
 			strconv.Itoa(r.TeamA),
 			strconv.Itoa(r.TeamB),
-			fmtF(r.DSeed),
-			fmtF(r.DElo),
-			fmtF(r.DWinPct),
-			fmtF(r.DAvgMargin),
-			fmtF(r.DAvgPF),
-			fmtF(r.DAvgPA),
-			fmtF(r.DMasseyOrd),
-			fmtF(r.Label),
-			strconv.FormatBool(r.HasLabel),
 		}
+		for _, v := range r.Features {
+			rec = append(rec, fmtF(v))
+		}
+		rec = append(rec, fmtF(r.Label), strconv.FormatBool(r.HasLabel))
 		if err := w.Write(rec); err != nil {
 			return "", err
 		}
@@ -128,63 +126,54 @@ func WriteMatchupsCSV(outDir, name string, rows []MatchupFeatureRow) (string, er
 	return path, nil
 }
 
-func ReadMatchupsCSV(path string) ([]MatchupFeatureRow, error) {
+// ReadMatchupsCSV reads a matchup table; every column other than the keys,
+// Label and HasLabel is a feature, returned in file order.
+func ReadMatchupsCSV(path string) ([]MatchupFeatureRow, []string, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer f.Close()
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = -1
-
-	header, err := r.Read()
+	header, err := csv.NewReader(f).Read()
 	if err != nil {
-		return nil, err
-	}
-	col := indexMap(header)
-
-	var out []MatchupFeatureRow
-	for {
-		rec, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return nil, err
+		return nil, nil, fmt.Errorf("%s: read header: %w", path, err)
+	}
+	var features []string
+	for _, c := range header {
+		switch c {
+		case "ID", "Season", "TeamA", "TeamB", "Label", "HasLabel":
+		default:
+			features = append(features, c)
 		}
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
 
+	rows, _, err := readCSV(f, path, matchupKeyCols, false, func(p *rowParser) MatchupFeatureRow {
 		row := MatchupFeatureRow{
-			ID:         getStr(rec, col, "ID"),
-			Season:     mustInt(rec, col, "Season"),
-			TeamA:      mustInt(rec, col, "TeamA"),
-			TeamB:      mustInt(rec, col, "TeamB"),
-			DSeed:      mustFloat(rec, col, "DSeed"),
-			DElo:       mustFloat(rec, col, "DElo"),
-			DWinPct:    mustFloat(rec, col, "DWinPct"),
-			DAvgMargin: mustFloat(rec, col, "DAvgMargin"),
-			DAvgPF:     mustFloat(rec, col, "DAvgPF"),
-			DAvgPA:     mustFloat(rec, col, "DAvgPA"),
-			DMasseyOrd: mustFloat(rec, col, "DMasseyOrd"),
-			Label:      0,
-			HasLabel:   false,
+			ID:       p.str("ID"),
+			Season:   p.int("Season"),
+			TeamA:    p.int("TeamA"),
+			TeamB:    p.int("TeamB"),
+			Features: make([]float64, len(features)),
+		}
+		for i, name := range features {
+			row.Features[i] = p.float(name)
 		}
 
 		// Optional columns:
-		hs := strings.ToLower(getStr(rec, col, "HasLabel"))
-		if hs == "true" {
+		if strings.ToLower(p.str("HasLabel")) == "true" {
 			row.HasLabel = true
 		}
-
-		ls := getStr(rec, col, "Label")
-		if strings.TrimSpace(ls) != "" {
-			if v, err := atof(ls); err == nil {
-				row.Label = v
-			}
+		if p.str("Label") != "" {
+			row.Label = p.float("Label")
 		}
-
-		out = append(out, row)
+		return row
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return out, nil
+	return rows, features, nil
 }
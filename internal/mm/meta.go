@@ -0,0 +1,138 @@
+package mm
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+type seasonKey struct {
+	League League
+	Season int
+}
+
+// TeamMeta answers team, conference and season lookups built from the
+// Teams, TeamConferences, Conferences and Seasons files.
+type TeamMeta struct {
+	teams    map[int]TeamRow
+	conf     map[[2]int]string // [season, team] -> ConfAbbrev
+	confDesc map[string]string
+	seasons  map[seasonKey]SeasonRow
+}
+
+func NewTeamMeta(
+	teams []TeamRow,
+	teamConfs []TeamConferenceRow,
+	confs []ConferenceRow,
+	seasons []SeasonRow,
+) *TeamMeta {
+	m := &TeamMeta{
+		teams:    make(map[int]TeamRow, len(teams)),
+		conf:     make(map[[2]int]string, len(teamConfs)),
+		confDesc: make(map[string]string, len(confs)),
+		seasons:  make(map[seasonKey]SeasonRow, len(seasons)),
+	}
+	for _, t := range teams {
+		m.teams[t.TeamID] = t
+	}
+	for _, c := range teamConfs {
+		m.conf[[2]int{c.Season, c.TeamID}] = c.ConfAbbrev
+	}
+	for _, c := range confs {
+		m.confDesc[c.ConfAbbrev] = c.Description
+	}
+	for _, s := range seasons {
+		m.seasons[seasonKey{s.League, s.Season}] = s
+	}
+	return m
+}
+
+// LoadTeamMeta reads the metadata tables for the given leagues. Every table
+// is optional; a missing file leaves the corresponding lookups empty.
+func LoadTeamMeta(l *Loader, leagues ...League) (*TeamMeta, error) {
+	teams, err := l.Teams(leagues...)
+	if err != nil && !errors.Is(err, ErrNotPresent) {
+		return nil, err
+	}
+	teamConfs, err := l.TeamConferences(leagues...)
+	if err != nil && !errors.Is(err, ErrNotPresent) {
+		return nil, err
+	}
+	confs, err := l.Conferences()
+	if err != nil && !errors.Is(err, ErrNotPresent) {
+		return nil, err
+	}
+	seasons, err := l.Seasons(leagues...)
+	if err != nil && !errors.Is(err, ErrNotPresent) {
+		return nil, err
+	}
+	return NewTeamMeta(teams, teamConfs, confs, seasons), nil
+}
+
+func (m *TeamMeta) Team(teamID int) (TeamRow, bool) {
+	t, ok := m.teams[teamID]
+	return t, ok
+}
+
+// TeamName returns the team's name, or "" when unknown.
+func (m *TeamMeta) TeamName(teamID int) string {
+	return m.teams[teamID].TeamName
+}
+
+// Conference returns the team's conference abbreviation in a season.
+func (m *TeamMeta) Conference(season, teamID int) (string, bool) {
+	c, ok := m.conf[[2]int{season, teamID}]
+	return c, ok
+}
+
+// ConferenceName returns the long name of a conference abbreviation.
+func (m *TeamMeta) ConferenceName(abbrev string) string {
+	if d, ok := m.confDesc[abbrev]; ok {
+		return d
+	}
+	return abbrev
+}
+
+// SameConference reports whether both teams belong to the same conference in
+// a season. Teams with unknown membership are never in the same conference.
+func (m *TeamMeta) SameConference(season, teamA, teamB int) bool {
+	a, okA := m.Conference(season, teamA)
+	b, okB := m.Conference(season, teamB)
+	return okA && okB && a == b
+}
+
+// ConferenceMembers lists the TeamIDs of a conference in a season, sorted.
+func (m *TeamMeta) ConferenceMembers(season int, abbrev string) []int {
+	var out []int
+	for k, c := range m.conf {
+		if k[0] == season && c == abbrev {
+			out = append(out, k[1])
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+func (m *TeamMeta) Season(league League, season int) (SeasonRow, bool) {
+	s, ok := m.seasons[seasonKey{league, season}]
+	return s, ok
+}
+
+// Date converts a DayNum to a calendar date using the season's DayZero.
+func (m *TeamMeta) Date(league League, season, dayNum int) (time.Time, bool) {
+	s, ok := m.Season(league, season)
+	if !ok {
+		return time.Time{}, false
+	}
+	return s.DayZero.AddDate(0, 0, dayNum), true
+}
+
+// AttachTeamMeta copies team names and conference membership into agg.
+func AttachTeamMeta(agg map[[2]int]*TeamSeasonAgg, meta *TeamMeta) {
+	for k, a := range agg {
+		a.TeamName = meta.TeamName(k[1])
+		if c, ok := meta.Conference(k[0], k[1]); ok {
+			a.ConfAbbrev = c
+		}
+	}
+}
@@ -2,32 +2,19 @@ package mm
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"io/fs"
 	"strconv"
 	"strings"
+	"time"
 )
 
-func findFile(dataDir string, candidates ...string) (string, error) {
-	for _, c := range candidates {
-		p := filepath.Join(dataDir, c)
-		if _, err := os.Stat(p); err == nil {
-			return p, nil
-		}
-	}
-	entries, _ := os.ReadDir(dataDir)
-	for _, e := range entries {
-		name := e.Name()
-		for _, c := range candidates {
-			if strings.EqualFold(name, c) {
-				return filepath.Join(dataDir, name), nil
-			}
-		}
-	}
-	return "", fmt.Errorf("file not found in %s: %v", dataDir, candidates)
-}
+// ErrNotPresent is returned (wrapped) when none of the candidate files for a
+// table exist. Optional tables should check for it with errors.Is to tell
+// "no data" apart from a real read failure.
+var ErrNotPresent = errors.New("not present")
 
 func atoi(s string) (int, error) {
 	s = strings.TrimSpace(s)
@@ -60,219 +47,680 @@ func getStr(rec []string, col map[string]int, name string) string {
 	return strings.TrimSpace(rec[i])
 }
 
-func mustInt(rec []string, col map[string]int, name string) int {
-	v := getStr(rec, col, name)
+// ParseError reports a cell that could not be parsed, with enough context to
+// find it in the source file.
+type ParseError struct {
+	File   string
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d: column %s=%q: %v", e.File, e.Line, e.Column, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// rowParser reads typed cells from one CSV record and keeps the first error,
+// so row constructors can be written as straight-line field assignments.
+type rowParser struct {
+	file string
+	line int
+	rec  []string
+	col  map[string]int
+	err  error
+}
+
+func (p *rowParser) fail(name, v string, err error) {
+	if p.err == nil {
+		p.err = &ParseError{File: p.file, Line: p.line, Column: name, Value: v, Err: err}
+	}
+}
+
+func (p *rowParser) str(name string) string {
+	return getStr(p.rec, p.col, name)
+}
+
+func (p *rowParser) int(name string) int {
+	v := p.str(name)
 	x, err := atoi(v)
 	if err != nil {
-		panic(fmt.Sprintf("parse int %s=%q: %v", name, v, err))
+		p.fail(name, v, err)
 	}
 	return x
 }
 
-func getIntDefault(rec []string, col map[string]int, name string, def int) int {
-	v := getStr(rec, col, name)
-	if v == "" {
+// intDefault returns def when the cell is empty or the column is absent.
+func (p *rowParser) intDefault(name string, def int) int {
+	if p.str(name) == "" {
 		return def
 	}
-	x, err := atoi(v)
+	return p.int(name)
+}
+
+func (p *rowParser) seed(name string) Seed {
+	v := p.str(name)
+	s, err := ParseSeed(v)
 	if err != nil {
-		return def
+		p.fail(name, v, err)
 	}
-	return x
+	return s
 }
 
-func mustFloat(rec []string, col map[string]int, name string) float64 {
-	v := getStr(rec, col, name)
+// date parses the M/D/YYYY dates used in the Seasons files.
+func (p *rowParser) date(name string) time.Time {
+	v := p.str(name)
+	t, err := time.Parse("1/2/2006", v)
+	if err != nil {
+		p.fail(name, v, err)
+	}
+	return t
+}
+
+func (p *rowParser) float(name string) float64 {
+	v := p.str(name)
 	x, err := atof(v)
 	if err != nil {
-		panic(fmt.Sprintf("parse float %s=%q: %v", name, v, err))
+		p.fail(name, v, err)
 	}
 	return x
 }
 
-func ReadRegularSeasonCompact(dataDir string) ([]RegularSeasonCompactRow, error) {
-	path, err := findFile(dataDir,
-		"MRegularSeasonCompactResults.csv",
-		"WRegularSeasonCompactResults.csv",
-	)
-	if err != nil {
-		return nil, err
+// floatDefault returns def when the cell is empty or the column is absent.
+func (p *rowParser) floatDefault(name string, def float64) float64 {
+	if p.str(name) == "" {
+		return def
 	}
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
+	return p.float(name)
+}
+
+// requireColumns reports the columns in names that are absent from the header.
+func requireColumns(col map[string]int, names []string) error {
+	var missing []string
+	for _, n := range names {
+		if _, ok := col[n]; !ok {
+			missing = append(missing, n)
+		}
 	}
-	defer f.Close()
+	if len(missing) > 0 {
+		return fmt.Errorf("missing columns %v", missing)
+	}
+	return nil
+}
 
-	r := csv.NewReader(f)
+// readCSV parses every data row of src with parse after checking that the
+// header contains all required columns. path only labels errors. In lenient
+// mode rows that fail to parse are skipped and counted in dropped; otherwise
+// the first bad row is returned as a *ParseError.
+func readCSV[T any](src io.Reader, path string, required []string, lenient bool, parse func(p *rowParser) T) (out []T, dropped int, err error) {
+	r := csv.NewReader(src)
 	r.FieldsPerRecord = -1
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, err
+		return nil, 0, fmt.Errorf("%s: read header: %w", path, err)
 	}
 	col := indexMap(header)
+	if err := requireColumns(col, required); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", path, err)
+	}
 
-	var out []RegularSeasonCompactRow
 	for {
 		rec, err := r.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, err
+			var perr *csv.ParseError
+			if lenient && errors.As(err, &perr) {
+				dropped++
+				continue
+			}
+			return nil, 0, fmt.Errorf("%s: %w", path, err)
+		}
+		line, _ := r.FieldPos(0)
+		p := rowParser{file: path, line: line, rec: rec, col: col}
+		row := parse(&p)
+		if p.err != nil {
+			if lenient {
+				dropped++
+				continue
+			}
+			return nil, 0, p.err
 		}
+		out = append(out, row)
+	}
+	return out, dropped, nil
+}
 
-		row := RegularSeasonCompactRow{}
-		row.Season = mustInt(rec, col, "Season")
-		row.DayNum = mustInt(rec, col, "DayNum")
-		row.WTeamID = mustInt(rec, col, "WTeamID")
-		row.WScore = mustInt(rec, col, "WScore")
-		row.LTeamID = mustInt(rec, col, "LTeamID")
-		row.LScore = mustInt(rec, col, "LScore")
-		row.WLoc = getStr(rec, col, "WLoc")
-		row.NumOT = getIntDefault(rec, col, "NumOT", 0)
+// Loader reads the Kaggle tables from a data source.
+type Loader struct {
+	// DataDir is a directory or a .zip archive holding the Kaggle CSVs.
+	DataDir string
+
+	// Lenient skips rows that fail to parse instead of returning an error.
+	Lenient bool
+	// Dropped counts the rows skipped in lenient mode, keyed by file path.
+	Dropped map[string]int
+
+	// CacheDir, when set, holds columnar snapshots of parsed tables that are
+	// reused while the source file is unchanged (see cache.go).
+	CacheDir string
+	// CacheHits counts the tables served from CacheDir.
+	CacheHits int
+
+	fsys   fs.FS
+	closer io.Closer
+	index  map[string]string
+	// stamps holds the stamp of every member hashed so far, so the cache and
+	// the manifest hash each file once per run.
+	stamps map[string]sourceStamp
+}
 
-		out = append(out, row)
+func NewLoader(dataDir string) *Loader {
+	return &Loader{DataDir: dataDir, Dropped: make(map[string]int)}
+}
+
+// TotalDropped sums Dropped over all files.
+func (l *Loader) TotalDropped() int {
+	n := 0
+	for _, d := range l.Dropped {
+		n += d
 	}
-	return out, nil
+	return n
 }
 
-func ReadTourneyCompact(dataDir string) ([]TourneyCompactRow, error) {
-	path, err := findFile(dataDir,
-		"MNCAATourneyCompactResults.csv",
-		"WNCAATourneyCompactResults.csv",
-	)
-	if err != nil {
-		return nil, err
+func (l *Loader) addDropped(path string, n int) {
+	if n == 0 {
+		return
+	}
+	if l.Dropped == nil {
+		l.Dropped = make(map[string]int)
+	}
+	l.Dropped[path] += n
+}
+
+// readTable parses the source member name, going through the snapshot cache
+// when CacheDir is set.
+func readTable[T any](l *Loader, name string, required []string, parse func(p *rowParser) T) ([]T, error) {
+	path := l.displayPath(name)
+
+	var stamp sourceStamp
+	if l.CacheDir != "" {
+		var err error
+		if stamp, err = l.stamp(name); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if rows, h, ok := loadCached[T](l, path, stamp); ok {
+			l.CacheHits++
+			l.addDropped(path, h.Dropped)
+			return rows, nil
+		}
 	}
-	f, err := os.Open(path)
+
+	f, err := l.open(name)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = -1
-
-	header, err := r.Read()
+	rows, dropped, err := readCSV(f, path, required, l.Lenient, parse)
 	if err != nil {
 		return nil, err
 	}
-	col := indexMap(header)
+	l.addDropped(path, dropped)
 
-	var out []TourneyCompactRow
-	for {
-		rec, err := r.Read()
-		if err == io.EOF {
-			break
+	if l.CacheDir != "" {
+		if err := storeCached(l, path, stamp, rows, dropped); err != nil {
+			return nil, fmt.Errorf("cache %s: %w", path, err)
 		}
+	}
+	return rows, nil
+}
+
+// readLeagueCSV reads the "M" and/or "W" variant of base for each requested
+// league and concatenates the rows. A league whose file is absent is skipped;
+// it is an error only if no league file exists at all.
+func readLeagueCSV[T any](
+	l *Loader,
+	base string,
+	leagues []League,
+	required []string,
+	parse func(p *rowParser, lg League) T,
+) ([]T, error) {
+	leagues = leaguesOrAll(leagues)
+
+	var out []T
+	found := false
+	var names []string
+	for _, lg := range leagues {
+		name := lg.FileName(base)
+		names = append(names, name)
+		member, err := l.find(name)
+		if errors.Is(err, ErrNotPresent) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		rows, err := readTable(l, member, required, func(p *rowParser) T {
+			return parse(p, lg)
+		})
 		if err != nil {
 			return nil, err
 		}
+		out = append(out, rows...)
+	}
+	if !found {
+		return nil, fmt.Errorf("file not found in %s: %v: %w", l.DataDir, names, ErrNotPresent)
+	}
+	return out, nil
+}
 
-		row := TourneyCompactRow{}
-		row.Season = mustInt(rec, col, "Season")
-		row.DayNum = mustInt(rec, col, "DayNum")
-		row.WTeamID = mustInt(rec, col, "WTeamID")
-		row.WScore = mustInt(rec, col, "WScore")
-		row.LTeamID = mustInt(rec, col, "LTeamID")
-		row.LScore = mustInt(rec, col, "LScore")
-		row.WLoc = getStr(rec, col, "WLoc")
-		row.NumOT = getIntDefault(rec, col, "NumOT", 0)
+var (
+	compactCols  = []string{"Season", "DayNum", "WTeamID", "WScore", "LTeamID", "LScore"}
+	seedCols     = []string{"Season", "Seed", "TeamID"}
+	masseyCols   = []string{"Season", "RankingDayNum", "SystemName", "TeamID", "OrdinalRank"}
+	teamCols     = []string{"TeamID", "TeamName"}
+	teamConfCols = []string{"Season", "TeamID", "ConfAbbrev"}
+	confCols     = []string{"ConfAbbrev", "Description"}
+	seasonCols   = []string{"Season", "DayZero"}
+	slotCols     = []string{"Season", "Slot", "StrongSeed", "WeakSeed"}
+	roundCols    = []string{"Seed", "GameRound", "GameSlot", "EarlyDayNum", "LateDayNum"}
+	cityCols     = []string{"CityID", "City", "State"}
+	gameCityCols = []string{"Season", "DayNum", "WTeamID", "LTeamID", "CRType", "CityID"}
+	confGameCols = []string{"Season", "ConfAbbrev", "DayNum", "WTeamID", "LTeamID"}
+
+	secondaryCols = append(append([]string{}, compactCols...), "SecondaryTourney")
+
+	boxScoreStats = []string{
+		"FGM", "FGA", "FGM3", "FGA3", "FTM", "FTA",
+		"OR", "DR", "Ast", "TO", "Stl", "Blk", "PF",
+	}
+	detailedCols = append(append(append([]string{}, compactCols...),
+		prefixed("W", boxScoreStats)...), prefixed("L", boxScoreStats)...)
+)
 
-		out = append(out, row)
+func prefixed(prefix string, names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = prefix + n
 	}
-	return out, nil
+	return out
 }
 
-func ReadSeeds(dataDir string) ([]SeedRow, error) {
-	path, err := findFile(dataDir,
-		"MNCAATourneySeeds.csv",
-		"WNCAATourneySeeds.csv",
-	)
-	if err != nil {
-		return nil, err
+// ReadRegularSeasonCompact loads the regular season results for the given
+// leagues (both when none are given).
+func ReadRegularSeasonCompact(dataDir string, leagues ...League) ([]RegularSeasonCompactRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.RegularSeasonCompact(leagues...)
+}
+
+func (l *Loader) RegularSeasonCompact(leagues ...League) ([]RegularSeasonCompactRow, error) {
+	return readLeagueCSV(l, "RegularSeasonCompactResults.csv", leagues, compactCols,
+		func(p *rowParser, lg League) RegularSeasonCompactRow {
+			return RegularSeasonCompactRow{
+				Season:  p.int("Season"),
+				League:  lg,
+				DayNum:  p.int("DayNum"),
+				WTeamID: p.int("WTeamID"),
+				WScore:  p.int("WScore"),
+				LTeamID: p.int("LTeamID"),
+				LScore:  p.int("LScore"),
+				WLoc:    p.str("WLoc"),
+				NumOT:   p.intDefault("NumOT", 0),
+			}
+		})
+}
+
+// ReadTourneyCompact loads the NCAA tournament results for the given leagues.
+func ReadTourneyCompact(dataDir string, leagues ...League) ([]TourneyCompactRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.TourneyCompact(leagues...)
+}
+
+func (l *Loader) TourneyCompact(leagues ...League) ([]TourneyCompactRow, error) {
+	return readLeagueCSV(l, "NCAATourneyCompactResults.csv", leagues, compactCols,
+		func(p *rowParser, lg League) TourneyCompactRow {
+			return TourneyCompactRow{
+				Season:  p.int("Season"),
+				League:  lg,
+				DayNum:  p.int("DayNum"),
+				WTeamID: p.int("WTeamID"),
+				WScore:  p.int("WScore"),
+				LTeamID: p.int("LTeamID"),
+				LScore:  p.int("LScore"),
+				WLoc:    p.str("WLoc"),
+				NumOT:   p.intDefault("NumOT", 0),
+			}
+		})
+}
+
+// ReadSecondaryTourneyCompact loads the NIT/CBI/... results for the given leagues.
+func ReadSecondaryTourneyCompact(dataDir string, leagues ...League) ([]SecondaryTourneyRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.SecondaryTourneyCompact(leagues...)
+}
+
+func (l *Loader) SecondaryTourneyCompact(leagues ...League) ([]SecondaryTourneyRow, error) {
+	return readLeagueCSV(l, "SecondaryTourneyCompactResults.csv", leagues, secondaryCols,
+		func(p *rowParser, lg League) SecondaryTourneyRow {
+			return SecondaryTourneyRow{
+				Season:           p.int("Season"),
+				League:           lg,
+				DayNum:           p.int("DayNum"),
+				WTeamID:          p.int("WTeamID"),
+				WScore:           p.int("WScore"),
+				LTeamID:          p.int("LTeamID"),
+				LScore:           p.int("LScore"),
+				WLoc:             p.str("WLoc"),
+				NumOT:            p.intDefault("NumOT", 0),
+				SecondaryTourney: p.str("SecondaryTourney"),
+			}
+		})
+}
+
+// ReadConferenceTourneyGames loads the list of conference tournament games.
+func ReadConferenceTourneyGames(dataDir string, leagues ...League) ([]ConferenceTourneyGameRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.ConferenceTourneyGames(leagues...)
+}
+
+func (l *Loader) ConferenceTourneyGames(leagues ...League) ([]ConferenceTourneyGameRow, error) {
+	return readLeagueCSV(l, "ConferenceTourneyGames.csv", leagues, confGameCols,
+		func(p *rowParser, lg League) ConferenceTourneyGameRow {
+			return ConferenceTourneyGameRow{
+				Season:     p.int("Season"),
+				League:     lg,
+				ConfAbbrev: p.str("ConfAbbrev"),
+				DayNum:     p.int("DayNum"),
+				WTeamID:    p.int("WTeamID"),
+				LTeamID:    p.int("LTeamID"),
+			}
+		})
+}
+
+// ReadRegularSeasonDetailed loads the regular season box scores for the given leagues.
+func ReadRegularSeasonDetailed(dataDir string, leagues ...League) ([]DetailedResultRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.RegularSeasonDetailed(leagues...)
+}
+
+func (l *Loader) RegularSeasonDetailed(leagues ...League) ([]DetailedResultRow, error) {
+	return readLeagueCSV(l, "RegularSeasonDetailedResults.csv", leagues, detailedCols, parseDetailed)
+}
+
+// ReadTourneyDetailed loads the NCAA tournament box scores for the given leagues.
+func ReadTourneyDetailed(dataDir string, leagues ...League) ([]DetailedResultRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.TourneyDetailed(leagues...)
+}
+
+func (l *Loader) TourneyDetailed(leagues ...League) ([]DetailedResultRow, error) {
+	return readLeagueCSV(l, "NCAATourneyDetailedResults.csv", leagues, detailedCols, parseDetailed)
+}
+
+func parseDetailed(p *rowParser, lg League) DetailedResultRow {
+	return DetailedResultRow{
+		Season:  p.int("Season"),
+		League:  lg,
+		DayNum:  p.int("DayNum"),
+		WTeamID: p.int("WTeamID"),
+		WScore:  p.int("WScore"),
+		LTeamID: p.int("LTeamID"),
+		LScore:  p.int("LScore"),
+		WLoc:    p.str("WLoc"),
+		NumOT:   p.intDefault("NumOT", 0),
+		W:       parseBoxScore(p, "W"),
+		L:       parseBoxScore(p, "L"),
 	}
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
+}
+
+func parseBoxScore(p *rowParser, prefix string) BoxScore {
+	return BoxScore{
+		FGM:  p.int(prefix + "FGM"),
+		FGA:  p.int(prefix + "FGA"),
+		FGM3: p.int(prefix + "FGM3"),
+		FGA3: p.int(prefix + "FGA3"),
+		FTM:  p.int(prefix + "FTM"),
+		FTA:  p.int(prefix + "FTA"),
+		OR:   p.int(prefix + "OR"),
+		DR:   p.int(prefix + "DR"),
+		Ast:  p.int(prefix + "Ast"),
+		TO:   p.int(prefix + "TO"),
+		Stl:  p.int(prefix + "Stl"),
+		Blk:  p.int(prefix + "Blk"),
+		PF:   p.int(prefix + "PF"),
 	}
-	defer f.Close()
+}
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = -1
+// ReadSeeds loads the NCAA tournament seeds for the given leagues.
+func ReadSeeds(dataDir string, leagues ...League) ([]SeedRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.Seeds(leagues...)
+}
 
-	header, err := r.Read()
+func (l *Loader) Seeds(leagues ...League) ([]SeedRow, error) {
+	return readLeagueCSV(l, "NCAATourneySeeds.csv", leagues, seedCols,
+		func(p *rowParser, lg League) SeedRow {
+			full := p.seed("Seed")
+			return SeedRow{
+				Season: p.int("Season"),
+				League: lg,
+				TeamID: p.int("TeamID"),
+				Seed:   full.Number,
+				Full:   full,
+			}
+		})
+}
+
+// ReadTourneySlots loads the bracket slots for the given leagues.
+func ReadTourneySlots(dataDir string, leagues ...League) ([]TourneySlotRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.TourneySlots(leagues...)
+}
+
+func (l *Loader) TourneySlots(leagues ...League) ([]TourneySlotRow, error) {
+	return readLeagueCSV(l, "NCAATourneySlots.csv", leagues, slotCols,
+		func(p *rowParser, lg League) TourneySlotRow {
+			return TourneySlotRow{
+				Season:     p.int("Season"),
+				League:     lg,
+				Slot:       p.str("Slot"),
+				StrongSeed: p.str("StrongSeed"),
+				WeakSeed:   p.str("WeakSeed"),
+			}
+		})
+}
+
+// ReadSeedRoundSlots loads the seed-to-slot map per round.
+func ReadSeedRoundSlots(dataDir string, leagues ...League) ([]SeedRoundSlotRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.SeedRoundSlots(leagues...)
+}
+
+func (l *Loader) SeedRoundSlots(leagues ...League) ([]SeedRoundSlotRow, error) {
+	return readLeagueCSV(l, "NCAATourneySeedRoundSlots.csv", leagues, roundCols,
+		func(p *rowParser, lg League) SeedRoundSlotRow {
+			return SeedRoundSlotRow{
+				League:      lg,
+				Seed:        p.str("Seed"),
+				GameRound:   p.int("GameRound"),
+				GameSlot:    p.str("GameSlot"),
+				EarlyDayNum: p.int("EarlyDayNum"),
+				LateDayNum:  p.int("LateDayNum"),
+			}
+		})
+}
+
+// ReadCities loads Cities.csv, which has no league prefix.
+func ReadCities(dataDir string) ([]CityRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.Cities()
+}
+
+func (l *Loader) Cities() ([]CityRow, error) {
+	member, err := l.find("Cities.csv")
 	if err != nil {
 		return nil, err
 	}
-	col := indexMap(header)
-
-	var out []SeedRow
-	for {
-		rec, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
+	return readTable(l, member, cityCols, func(p *rowParser) CityRow {
+		return CityRow{
+			CityID: p.int("CityID"),
+			City:   p.str("City"),
+			State:  p.str("State"),
 		}
+	})
+}
 
-		seedStr := getStr(rec, col, "Seed")
-		seedNum := parseSeedNumeric(seedStr)
+// ReadGameCities loads the game locations for the given leagues.
+func ReadGameCities(dataDir string, leagues ...League) ([]GameCityRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.GameCities(leagues...)
+}
 
-		out = append(out, SeedRow{
-			Season: mustInt(rec, col, "Season"),
-			TeamID: mustInt(rec, col, "TeamID"),
-			Seed:   seedNum,
+func (l *Loader) GameCities(leagues ...League) ([]GameCityRow, error) {
+	return readLeagueCSV(l, "GameCities.csv", leagues, gameCityCols,
+		func(p *rowParser, lg League) GameCityRow {
+			return GameCityRow{
+				Season:  p.int("Season"),
+				League:  lg,
+				DayNum:  p.int("DayNum"),
+				WTeamID: p.int("WTeamID"),
+				LTeamID: p.int("LTeamID"),
+				CRType:  p.str("CRType"),
+				CityID:  p.int("CityID"),
+			}
 		})
-	}
-	return out, nil
 }
 
-func ReadMassey(dataDir string) ([]MasseyRow, error) {
-	path, err := findFile(dataDir, "MMasseyOrdinals.csv", "WMasseyOrdinals.csv")
-	if err != nil {
-		return nil, nil // optional
-	}
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, nil
-	}
-	defer f.Close()
+// ReadMassey loads the Massey ordinals. Kaggle only publishes them for the
+// men's league. The table is optional: when no ordinals file exists the error
+// wraps ErrNotPresent, any other error means the file exists but could not be
+// read in full.
+func ReadMassey(dataDir string, leagues ...League) ([]MasseyRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.Massey(leagues...)
+}
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = -1
+func (l *Loader) Massey(leagues ...League) ([]MasseyRow, error) {
+	return readLeagueCSV(l, "MasseyOrdinals.csv", leagues, masseyCols,
+		func(p *rowParser, lg League) MasseyRow {
+			return MasseyRow{
+				Season:     p.int("Season"),
+				League:     lg,
+				TeamID:     p.int("TeamID"),
+				RankingDay: p.int("RankingDayNum"),
+				System:     p.str("SystemName"),
+				Ordinal:    p.int("OrdinalRank"),
+			}
+		})
+}
 
-	header, err := r.Read()
+// ReadTeams loads MTeams.csv / WTeams.csv.
+func ReadTeams(dataDir string, leagues ...League) ([]TeamRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.Teams(leagues...)
+}
+
+func (l *Loader) Teams(leagues ...League) ([]TeamRow, error) {
+	return readLeagueCSV(l, "Teams.csv", leagues, teamCols,
+		func(p *rowParser, lg League) TeamRow {
+			return TeamRow{
+				League:        lg,
+				TeamID:        p.int("TeamID"),
+				TeamName:      p.str("TeamName"),
+				FirstD1Season: p.intDefault("FirstD1Season", 0),
+				LastD1Season:  p.intDefault("LastD1Season", 0),
+			}
+		})
+}
+
+// ReadTeamConferences loads the per-season conference membership.
+func ReadTeamConferences(dataDir string, leagues ...League) ([]TeamConferenceRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.TeamConferences(leagues...)
+}
+
+func (l *Loader) TeamConferences(leagues ...League) ([]TeamConferenceRow, error) {
+	return readLeagueCSV(l, "TeamConferences.csv", leagues, teamConfCols,
+		func(p *rowParser, lg League) TeamConferenceRow {
+			return TeamConferenceRow{
+				Season:     p.int("Season"),
+				League:     lg,
+				TeamID:     p.int("TeamID"),
+				ConfAbbrev: p.str("ConfAbbrev"),
+			}
+		})
+}
+
+// ReadConferences loads Conferences.csv, which has no league prefix.
+func ReadConferences(dataDir string) ([]ConferenceRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.Conferences()
+}
+
+func (l *Loader) Conferences() ([]ConferenceRow, error) {
+	member, err := l.find("Conferences.csv")
 	if err != nil {
-		return nil, nil
+		return nil, err
 	}
-	col := indexMap(header)
-
-	var out []MasseyRow
-	for {
-		rec, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, nil
+	return readTable(l, member, confCols, func(p *rowParser) ConferenceRow {
+		return ConferenceRow{
+			ConfAbbrev:  p.str("ConfAbbrev"),
+			Description: p.str("Description"),
 		}
-		out = append(out, MasseyRow{
-			Season:     mustInt(rec, col, "Season"),
-			TeamID:     mustInt(rec, col, "TeamID"),
-			RankingDay: mustInt(rec, col, "RankingDayNum"),
-			System:     getStr(rec, col, "SystemName"),
-			Ordinal:    mustInt(rec, col, "OrdinalRank"),
+	})
+}
+
+// ReadSeasons loads MSeasons.csv / WSeasons.csv.
+func ReadSeasons(dataDir string, leagues ...League) ([]SeasonRow, error) {
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.Seasons(leagues...)
+}
+
+func (l *Loader) Seasons(leagues ...League) ([]SeasonRow, error) {
+	return readLeagueCSV(l, "Seasons.csv", leagues, seasonCols,
+		func(p *rowParser, lg League) SeasonRow {
+			return SeasonRow{
+				Season:  p.int("Season"),
+				League:  lg,
+				DayZero: p.date("DayZero"),
+				RegionW: p.str("RegionW"),
+				RegionX: p.str("RegionX"),
+				RegionY: p.str("RegionY"),
+				RegionZ: p.str("RegionZ"),
+			}
 		})
-	}
-	return out, nil
 }
 
+// ReadSampleSubmission returns the matchup IDs of the sample submission.
 func ReadSampleSubmission(dataDir string) ([]string, error) {
-	path, err := findFile(dataDir,
+	l := NewLoader(dataDir)
+	defer l.Close()
+	return l.SampleSubmission()
+}
+
+func (l *Loader) SampleSubmission() ([]string, error) {
+	member, err := l.find(
 		"SampleSubmissionStage1.csv",
 		"SampleSubmissionStage2.csv",
 		"MSampleSubmissionStage1.csv",
@@ -281,55 +729,17 @@ func ReadSampleSubmission(dataDir string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	f, err := os.Open(path)
+	ids, err := readTable(l, member, []string{"ID"}, func(p *rowParser) string {
+		return p.str("ID")
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = -1
-
-	header, err := r.Read()
-	if err != nil {
-		return nil, err
-	}
-	col := indexMap(header)
-
-	var out []string
-	for {
-		rec, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		id := getStr(rec, col, "ID")
+	out := ids[:0]
+	for _, id := range ids {
 		if id != "" {
 			out = append(out, id)
 		}
 	}
 	return out, nil
 }
-
-func parseSeedNumeric(seed string) int {
-	seed = strings.TrimSpace(seed)
-	if seed == "" {
-		return 0
-	}
-	var digits strings.Builder
-	for _, ch := range seed {
-		if ch >= '0' && ch <= '9' {
-			digits.WriteRune(ch)
-		}
-	}
-	if digits.Len() == 0 {
-		return 0
-	}
-	n, err := strconv.Atoi(digits.String())
-	if err != nil {
-		return 0
-	}
-	return n
-}
@@ -0,0 +1,272 @@
+package mm
+
+import (
+	"fmt"
+	"math"
+)
+
+// The feature registry names every team-level column of
+// team_season_features.csv and every matchup-level column of the
+// features_*.csv tables. New features register here once; JoinFeatures, the
+// CSV writers and train/predict pick them up by name.
+
+// TeamFeature reads one value of a team-season. A nil Value reads
+// TeamSeasonAgg.Extra[Name].
+type TeamFeature struct {
+	Name  string
+	Value func(a *TeamSeasonAgg) float64
+}
+
+func (f TeamFeature) of(a *TeamSeasonAgg) float64 {
+	if f.Value == nil {
+		return a.Extra[f.Name]
+	}
+	return f.Value(a)
+}
+
+// MatchupFeature computes one value of a matchup from team A and team B.
+// Default features are the ones train uses when none are selected. A value
+// is NaN when a team lacks the input; train and predict fill it with the
+// training mean.
+type MatchupFeature struct {
+	Name    string
+	Value   func(a, b *TeamSeasonAgg) float64
+	Default bool
+}
+
+var (
+	teamFeatures    []TeamFeature
+	matchupFeatures []MatchupFeature
+)
+
+func RegisterTeamFeature(f TeamFeature) {
+	for _, g := range teamFeatures {
+		if g.Name == f.Name {
+			panic("mm: team feature registered twice: " + f.Name)
+		}
+	}
+	teamFeatures = append(teamFeatures, f)
+}
+
+func RegisterMatchupFeature(f MatchupFeature) {
+	for _, g := range matchupFeatures {
+		if g.Name == f.Name {
+			panic("mm: matchup feature registered twice: " + f.Name)
+		}
+	}
+	matchupFeatures = append(matchupFeatures, f)
+}
+
+// TeamFeatures returns the registered team features in registration order.
+func TeamFeatures() []TeamFeature {
+	return append([]TeamFeature{}, teamFeatures...)
+}
+
+// MatchupFeatures returns the registered matchup features in registration order.
+func MatchupFeatures() []MatchupFeature {
+	return append([]MatchupFeature{}, matchupFeatures...)
+}
+
+func MatchupFeatureNames() []string {
+	out := make([]string, len(matchupFeatures))
+	for i, f := range matchupFeatures {
+		out[i] = f.Name
+	}
+	return out
+}
+
+func DefaultMatchupFeatureNames() []string {
+	var out []string
+	for _, f := range matchupFeatures {
+		if f.Default {
+			out = append(out, f.Name)
+		}
+	}
+	return out
+}
+
+func lookupTeamFeature(name string) TeamFeature {
+	for _, f := range teamFeatures {
+		if f.Name == name {
+			return f
+		}
+	}
+	panic("mm: unknown team feature " + name)
+}
+
+// DiffFeature is team A's value of a team feature minus team B's.
+func DiffFeature(name, team string, def bool) MatchupFeature {
+	f := lookupTeamFeature(team)
+	return MatchupFeature{Name: name, Default: def, Value: func(a, b *TeamSeasonAgg) float64 {
+		return f.of(a) - f.of(b)
+	}}
+}
+
+// ZFeature is the gap in a team feature divided by the combined standard
+// deviation from another, so unlike the deviations themselves it changes sign
+// when A and B are swapped.
+func ZFeature(name, team, sd string, def bool) MatchupFeature {
+	f, s := lookupTeamFeature(team), lookupTeamFeature(sd)
+	return MatchupFeature{Name: name, Default: def, Value: func(a, b *TeamSeasonAgg) float64 {
+		return zDiff(f.of(a)-f.of(b), s.of(a), s.of(b))
+	}}
+}
+
+// zDiff divides a rating gap by the standard deviation of the gap, or is 0
+// when neither side has one.
+func zDiff(d, sa, sb float64) float64 {
+	sd := math.Hypot(sa, sb)
+	if sd == 0 {
+		return 0
+	}
+	return d / sd
+}
+
+// seedGap is team A's seed minus team B's. Unseeded teams (Seed 0), such as
+// those in secondary tournaments, have no seed gap.
+func seedGap(a, b *TeamSeasonAgg) float64 {
+	if a.Seed == 0 || b.Seed == 0 {
+		return math.NaN()
+	}
+	return a.Seed - b.Seed
+}
+
+// FeatureColumns maps the wanted feature names to their positions in have.
+func FeatureColumns(have, want []string) ([]int, error) {
+	pos := make(map[string]int, len(have))
+	for i, n := range have {
+		pos[n] = i
+	}
+	out := make([]int, len(want))
+	var missing []string
+	for i, n := range want {
+		p, ok := pos[n]
+		if !ok {
+			missing = append(missing, n)
+			continue
+		}
+		out[i] = p
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unknown features %v", missing)
+	}
+	return out, nil
+}
+
+// SelectFeatures returns the feature vector of every row restricted to cols.
+func SelectFeatures(rows []MatchupFeatureRow, cols []int) [][]float64 {
+	out := make([][]float64, len(rows))
+	for i, r := range rows {
+		x := make([]float64, len(cols))
+		for j, c := range cols {
+			x[j] = r.Features[c]
+		}
+		out[i] = x
+	}
+	return out
+}
+
+func init() {
+	for _, f := range []TeamFeature{
+		{"WinPct", func(a *TeamSeasonAgg) float64 { return a.WinPct }},
+		{"AvgPF", func(a *TeamSeasonAgg) float64 { return a.AvgPF }},
+		{"AvgPA", func(a *TeamSeasonAgg) float64 { return a.AvgPA }},
+		{"AvgMargin", func(a *TeamSeasonAgg) float64 { return a.AvgMargin }},
+		{"EloEnd", func(a *TeamSeasonAgg) float64 { return a.EloEnd }},
+		{"Seed", func(a *TeamSeasonAgg) float64 { return a.Seed }},
+		{"MasseyOrdinal", func(a *TeamSeasonAgg) float64 { return a.MasseyOrdinal }},
+		{"Glicko", func(a *TeamSeasonAgg) float64 { return a.Glicko }},
+		{"GlickoRD", func(a *TeamSeasonAgg) float64 { return a.GlickoRD }},
+		{"MasseyRating", func(a *TeamSeasonAgg) float64 { return a.MasseyRating }},
+		{"MasseyOff", func(a *TeamSeasonAgg) float64 { return a.MasseyOff }},
+		{"MasseyDef", func(a *TeamSeasonAgg) float64 { return a.MasseyDef }},
+		{"Colley", func(a *TeamSeasonAgg) float64 { return a.Colley }},
+		{"LRMC", func(a *TeamSeasonAgg) float64 { return a.LRMC }},
+		{"BT", func(a *TeamSeasonAgg) float64 { return a.BT }},
+		{"BTSE", func(a *TeamSeasonAgg) float64 { return a.BTSE }},
+		{"AdjO", func(a *TeamSeasonAgg) float64 { return a.AdjO }},
+		{"AdjD", func(a *TeamSeasonAgg) float64 { return a.AdjD }},
+		{"AdjEM", func(a *TeamSeasonAgg) float64 { return a.AdjEM }},
+		{"AdjTempo", func(a *TeamSeasonAgg) float64 { return a.AdjTempo }},
+		{"RidgeOff", func(a *TeamSeasonAgg) float64 { return a.RidgeOff }},
+		{"RidgeDef", func(a *TeamSeasonAgg) float64 { return a.RidgeDef }},
+		{"RidgeNet", func(a *TeamSeasonAgg) float64 { return a.RidgeNet }},
+		{"RidgeNetSD", func(a *TeamSeasonAgg) float64 { return a.RidgeNetSD }},
+
+		// Schedule strength, see AttachSOS.
+		{Name: "OppRating"},
+		{Name: "OWP"},
+		{Name: "OOWP"},
+		{Name: "RPI"},
+		{Name: "TopWins"},
+		{Name: "TopGames"},
+		{Name: "TopWinPct"},
+		{Name: "Q1Wins"},
+		{Name: "Q1Losses"},
+		{Name: "Q2Wins"},
+		{Name: "Q2Losses"},
+		{Name: "Q3Wins"},
+		{Name: "Q3Losses"},
+		{Name: "Q4Wins"},
+		{Name: "Q4Losses"},
+
+		// Recent form, see AttachForm.
+		{Name: "FormWinPct"},
+		{Name: "FormMargin"},
+		{Name: "FormElo"},
+		{Name: "DaysWinPct"},
+		{Name: "DaysMargin"},
+		{Name: "DaysElo"},
+		{Name: "DecayMargin"},
+		{Name: "DecayEff"},
+	} {
+		RegisterTeamFeature(f)
+	}
+
+	for _, f := range []MatchupFeature{
+		{Name: "DSeed", Value: seedGap, Default: true},
+		DiffFeature("DElo", "EloEnd", true),
+		DiffFeature("DWinPct", "WinPct", true),
+		DiffFeature("DAvgMargin", "AvgMargin", true),
+		DiffFeature("DAvgPF", "AvgPF", true),
+		DiffFeature("DAvgPA", "AvgPA", true),
+		DiffFeature("DMasseyOrd", "MasseyOrdinal", true),
+		// Of the features below, only the ones that lowered LOSO Brier when
+		// added to the seven above are defaults; the rest are opt-in with
+		// --features.
+		DiffFeature("DGlicko", "Glicko", false),
+		ZFeature("DGlickoZ", "Glicko", "GlickoRD", false),
+		DiffFeature("DMasseyRating", "MasseyRating", true),
+		DiffFeature("DColley", "Colley", false),
+		DiffFeature("DLRMC", "LRMC", false),
+		DiffFeature("DBT", "BT", false),
+		DiffFeature("DAdjO", "AdjO", false),
+		DiffFeature("DAdjD", "AdjD", false),
+		DiffFeature("DAdjEM", "AdjEM", false),
+		DiffFeature("DAdjTempo", "AdjTempo", false),
+		DiffFeature("DRidge", "RidgeNet", true),
+		ZFeature("DRidgeZ", "RidgeNet", "RidgeNetSD", false),
+
+		DiffFeature("DOppRating", "OppRating", true),
+		DiffFeature("DRPI", "RPI", false),
+		DiffFeature("DOWP", "OWP", false),
+		DiffFeature("DOOWP", "OOWP", false),
+		DiffFeature("DTopWins", "TopWins", false),
+		DiffFeature("DTopWinPct", "TopWinPct", false),
+		DiffFeature("DQ1Wins", "Q1Wins", false),
+		DiffFeature("DQ2Wins", "Q2Wins", false),
+		DiffFeature("DQ3Losses", "Q3Losses", false),
+		DiffFeature("DQ4Losses", "Q4Losses", false),
+
+		DiffFeature("DFormWinPct", "FormWinPct", false),
+		DiffFeature("DFormMargin", "FormMargin", false),
+		DiffFeature("DFormElo", "FormElo", false),
+		DiffFeature("DDaysWinPct", "DaysWinPct", false),
+		DiffFeature("DDaysMargin", "DaysMargin", false),
+		DiffFeature("DDaysElo", "DaysElo", false),
+		DiffFeature("DDecayMargin", "DecayMargin", false),
+		DiffFeature("DDecayEff", "DecayEff", false),
+	} {
+		RegisterMatchupFeature(f)
+	}
+}
@@ -0,0 +1,147 @@
+package mm
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ridge offense/defense ratings fitted per season and league. Each team's
+// points in a game are modelled as
+//
+//	points = mean + home·h + off(team) - def(opponent) + noise
+//
+// with a Gaussian prior of precision Lambda (relative to the noise) on every
+// off and def, which is ridge regression. The posterior covariance
+// σ²·(XᵀX + Λ)⁻¹ gives each rating a standard deviation.
+
+type RidgeConfig struct {
+	Lambda  float64
+	FitHome bool
+}
+
+func DefaultRidgeConfig() RidgeConfig {
+	return RidgeConfig{Lambda: 2, FitHome: true}
+}
+
+// RidgeRating is a team's ratings in points per game against an average
+// opponent; Net = Off + Def, so a higher Def is a better defense.
+type RidgeRating struct {
+	Off   float64
+	Def   float64
+	Net   float64
+	OffSD float64
+	DefSD float64
+	NetSD float64
+}
+
+// BuildRidgeRatings fits every season and league in rows.
+func BuildRidgeRatings(rows []RegularSeasonCompactRow, cfg RidgeConfig) (map[[2]int]RidgeRating, error) {
+	out := make(map[[2]int]RidgeRating)
+	for _, sg := range groupSeasons(rows) {
+		r, err := fitRidge(sg, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("ridge %s %d: %w", sg.League, sg.Season, err)
+		}
+		for i, t := range sg.Teams {
+			out[[2]int{sg.Season, t}] = r[i]
+		}
+	}
+	return out, nil
+}
+
+func fitRidge(sg *seasonGroup, cfg RidgeConfig) ([]RidgeRating, error) {
+	n := len(sg.Teams)
+	// Unknowns: off 0..n-1, def n..2n-1, mean 2n, home 2n+1.
+	muIdx, homeIdx := 2*n, 2*n+1
+	dim := 2*n + 1
+	if cfg.FitHome {
+		dim++
+	}
+	a := newMatrix(dim)
+	b := make([]float64, dim)
+	obs := 0
+
+	observe := func(team, opp int, points, home float64) {
+		idx := []int{team, n + opp, muIdx}
+		coef := []float64{1, -1, 1}
+		if cfg.FitHome && home != 0 {
+			idx = append(idx, homeIdx)
+			coef = append(coef, home)
+		}
+		for p := range idx {
+			for q := range idx {
+				a.add(idx[p], idx[q], coef[p]*coef[q])
+			}
+			b[idx[p]] += coef[p] * points
+		}
+		obs++
+	}
+	for _, g := range sg.Games {
+		w, l := sg.index[g.WTeamID], sg.index[g.LTeamID]
+		hs := homeSign(g.WLoc)
+		observe(w, l, float64(g.WScore), hs)
+		observe(l, w, float64(g.LScore), -hs)
+	}
+	for i := 0; i < 2*n; i++ {
+		a.add(i, i, cfg.Lambda)
+	}
+	if cfg.FitHome && a.at(homeIdx, homeIdx) == 0 {
+		a.add(homeIdx, homeIdx, 1) // no home games: keep the home term at 0
+	}
+
+	chol, err := factorCholesky(a)
+	if err != nil {
+		return nil, err
+	}
+	x := chol.solve(b)
+
+	// The noise variance comes from the residuals of the fit.
+	rss := 0.0
+	for _, g := range sg.Games {
+		w, l := sg.index[g.WTeamID], sg.index[g.LTeamID]
+		hs := 0.0
+		if cfg.FitHome {
+			hs = homeSign(g.WLoc) * x[homeIdx]
+		}
+		ew := float64(g.WScore) - (x[muIdx] + hs + x[w] - x[n+l])
+		el := float64(g.LScore) - (x[muIdx] - hs + x[l] - x[n+w])
+		rss += ew*ew + el*el
+	}
+	sigma2 := rss / math.Max(float64(obs-dim), 1)
+
+	// var(off_i), var(def_i) and cov(off_i, def_i) for every team.
+	pairs := make([][2]int, 0, 3*n)
+	for i := 0; i < n; i++ {
+		pairs = append(pairs, [2]int{i, i}, [2]int{n + i, n + i}, [2]int{i, n + i})
+	}
+	inv := chol.inverseAt(pairs)
+	out := make([]RidgeRating, n)
+	for i := range out {
+		varOff := sigma2 * inv[3*i]
+		varDef := sigma2 * inv[3*i+1]
+		cov := sigma2 * inv[3*i+2]
+		out[i] = RidgeRating{
+			Off:   x[i],
+			Def:   x[n+i],
+			Net:   x[i] + x[n+i],
+			OffSD: math.Sqrt(varOff),
+			DefSD: math.Sqrt(varDef),
+			NetSD: math.Sqrt(math.Max(varOff+varDef+2*cov, 0)),
+		}
+	}
+	return out, nil
+}
+
+func AttachRidgeRatings(agg map[[2]int]*TeamSeasonAgg, ratings map[[2]int]RidgeRating) {
+	for k, r := range ratings {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.RidgeOff = r.Off
+		a.RidgeDef = r.Def
+		a.RidgeNet = r.Net
+		a.RidgeNetSD = r.NetSD
+	}
+}
@@ -0,0 +1,42 @@
+package mm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRidgeRecoversRatings(t *testing.T) {
+	// Scores follow 70 + off(team) - def(opp) exactly, with off = [5 0 -5]
+	// and def = [3 0 -3]. The ratings are only identified up to a shift, so
+	// compare differences.
+	off := map[int]float64{1: 5, 2: 0, 3: -5}
+	def := map[int]float64{1: 3, 2: 0, 3: -3}
+	var rows []RegularSeasonCompactRow
+	for rep := 0; rep < 2; rep++ {
+		rows = append(rows,
+			neutralGame(1, 75, 2, 67),
+			neutralGame(1, 78, 3, 62),
+			neutralGame(2, 73, 3, 65),
+		)
+	}
+	got, err := BuildRidgeRatings(rows, RidgeConfig{Lambda: 1e-6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := got[[2]int{2024, 2}]
+	for team := 1; team <= 3; team++ {
+		r := got[[2]int{2024, team}]
+		if d := r.Off - base.Off; math.Abs(d-off[team]) > 1e-3 {
+			t.Errorf("team %d off gap %v, want %v", team, d, off[team])
+		}
+		if d := r.Def - base.Def; math.Abs(d-def[team]) > 1e-3 {
+			t.Errorf("team %d def gap %v, want %v", team, d, def[team])
+		}
+		if math.Abs(r.Net-(r.Off+r.Def)) > 1e-9 {
+			t.Errorf("team %d Net %v != Off + Def", team, r.Net)
+		}
+		if !(r.NetSD >= 0) || math.IsInf(r.NetSD, 0) {
+			t.Errorf("team %d NetSD %v", team, r.NetSD)
+		}
+	}
+}
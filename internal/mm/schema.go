@@ -1,7 +1,13 @@
 package mm
 
+import (
+	"fmt"
+	"time"
+)
+
 type RegularSeasonCompactRow struct {
 	Season  int
+	League  League
 	DayNum  int
 	WTeamID int
 	WScore  int
@@ -9,10 +15,13 @@ type RegularSeasonCompactRow struct {
 	LScore  int
 	WLoc    string
 	NumOT   int
+
+	Kind GameKind // set by TagGames; empty when untagged
 }
 
 type TourneyCompactRow struct {
 	Season  int
+	League  League
 	DayNum  int
 	WTeamID int
 	WScore  int
@@ -22,24 +31,193 @@ type TourneyCompactRow struct {
 	NumOT   int
 }
 
+// SecondaryTourneyRow is a postseason game outside the NCAA tournament (NIT,
+// CBI, ...), from the SecondaryTourneyCompactResults files.
+type SecondaryTourneyRow struct {
+	Season           int
+	League           League
+	DayNum           int
+	WTeamID          int
+	WScore           int
+	LTeamID          int
+	LScore           int
+	WLoc             string
+	NumOT            int
+	SecondaryTourney string
+}
+
+// Regular converts the game to a regular-season row tagged GameSecondary.
+func (g SecondaryTourneyRow) Regular() RegularSeasonCompactRow {
+	return RegularSeasonCompactRow{
+		Season: g.Season, League: g.League, DayNum: g.DayNum,
+		WTeamID: g.WTeamID, WScore: g.WScore, LTeamID: g.LTeamID, LScore: g.LScore,
+		WLoc: g.WLoc, NumOT: g.NumOT, Kind: GameSecondary,
+	}
+}
+
+// Tourney converts the game to a tournament row, e.g. to train on it.
+func (g SecondaryTourneyRow) Tourney() TourneyCompactRow {
+	return TourneyCompactRow{
+		Season: g.Season, League: g.League, DayNum: g.DayNum,
+		WTeamID: g.WTeamID, WScore: g.WScore, LTeamID: g.LTeamID, LScore: g.LScore,
+		WLoc: g.WLoc, NumOT: g.NumOT,
+	}
+}
+
+// ConferenceTourneyGameRow identifies a conference tournament game inside the
+// regular season results.
+type ConferenceTourneyGameRow struct {
+	Season     int
+	League     League
+	ConfAbbrev string
+	DayNum     int
+	WTeamID    int
+	LTeamID    int
+}
+
+// BoxScore is one team's line from the detailed results files.
+type BoxScore struct {
+	FGM  int
+	FGA  int
+	FGM3 int
+	FGA3 int
+	FTM  int
+	FTA  int
+	OR   int
+	DR   int
+	Ast  int
+	TO   int
+	Stl  int
+	Blk  int
+	PF   int
+}
+
+// DetailedResultRow is a game from the *DetailedResults.csv files: the compact
+// result plus the box score of the winner (W) and loser (L).
+type DetailedResultRow struct {
+	Season  int
+	League  League
+	DayNum  int
+	WTeamID int
+	WScore  int
+	LTeamID int
+	LScore  int
+	WLoc    string
+	NumOT   int
+
+	W BoxScore
+	L BoxScore
+}
+
+// Compact drops the box scores.
+func (g DetailedResultRow) Compact() RegularSeasonCompactRow {
+	return RegularSeasonCompactRow{
+		Season: g.Season, League: g.League, DayNum: g.DayNum,
+		WTeamID: g.WTeamID, WScore: g.WScore, LTeamID: g.LTeamID, LScore: g.LScore,
+		WLoc: g.WLoc, NumOT: g.NumOT,
+	}
+}
+
 type SeedRow struct {
 	Season int
+	League League
 	TeamID int
-	Seed   int
+	Seed   int  // numeric seed, Full.Number
+	Full   Seed // region, number and play-in suffix
+}
+
+// TourneySlotRow is a row of the NCAATourneySlots files: the winners of
+// StrongSeed and WeakSeed (seed codes or earlier slots) meet in Slot.
+type TourneySlotRow struct {
+	Season     int
+	League     League
+	Slot       string
+	StrongSeed string
+	WeakSeed   string
+}
+
+// SeedRoundSlotRow is a row of the NCAATourneySeedRoundSlots files: the slot
+// and day range in which a seed plays each round.
+type SeedRoundSlotRow struct {
+	League      League
+	Seed        string
+	GameRound   int
+	GameSlot    string
+	EarlyDayNum int
+	LateDayNum  int
+}
+
+// CityRow is a row of Cities.csv, shared by both leagues.
+type CityRow struct {
+	CityID int
+	City   string
+	State  string
+}
+
+// GameCityRow is a row of the GameCities files. CRType tells which results
+// file the game belongs to ("Regular", "NCAA", "Secondary").
+type GameCityRow struct {
+	Season  int
+	League  League
+	DayNum  int
+	WTeamID int
+	LTeamID int
+	CRType  string
+	CityID  int
 }
 
 type MasseyRow struct {
 	Season     int
+	League     League
 	TeamID     int
 	RankingDay int
 	System     string
 	Ordinal    int
 }
 
+// TeamRow is a row of MTeams.csv / WTeams.csv. The women's file has no D1
+// season range, so those fields are zero there.
+type TeamRow struct {
+	League        League
+	TeamID        int
+	TeamName      string
+	FirstD1Season int
+	LastD1Season  int
+}
+
+// TeamConferenceRow is a row of MTeamConferences.csv / WTeamConferences.csv.
+type TeamConferenceRow struct {
+	Season     int
+	League     League
+	TeamID     int
+	ConfAbbrev string
+}
+
+// ConferenceRow is a row of Conferences.csv, shared by both leagues.
+type ConferenceRow struct {
+	ConfAbbrev  string
+	Description string
+}
+
+// SeasonRow is a row of MSeasons.csv / WSeasons.csv. DayNum 0 of a season is
+// DayZero; the Region fields name the bracket regions W, X, Y and Z.
+type SeasonRow struct {
+	Season  int
+	League  League
+	DayZero time.Time
+	RegionW string
+	RegionX string
+	RegionY string
+	RegionZ string
+}
+
 type TeamSeasonAgg struct {
 	Season int
 	TeamID int
 
+	TeamName   string
+	ConfAbbrev string
+
 	Games  int
 	Wins   int
 	Losses int
@@ -56,22 +234,88 @@ type TeamSeasonAgg struct {
 	EloEnd        float64
 	Seed          float64
 	MasseyOrdinal float64
+
+	Glicko   float64
+	GlickoRD float64
+
+	MasseyRating float64
+	MasseyOff    float64
+	MasseyDef    float64
+
+	Colley float64
+	LRMC   float64
+
+	BT   float64
+	BTSE float64
+
+	AdjO     float64
+	AdjD     float64
+	AdjEM    float64
+	AdjTempo float64
+
+	RidgeOff   float64
+	RidgeDef   float64
+	RidgeNet   float64
+	RidgeNetSD float64
+
+	// Extra holds registered team features that have no field above.
+	Extra map[string]float64
 }
 
+// Set stores a team feature in Extra.
+func (a *TeamSeasonAgg) Set(name string, v float64) {
+	if a.Extra == nil {
+		a.Extra = make(map[string]float64)
+	}
+	a.Extra[name] = v
+}
+
+// MatchupFeatureRow is one matchup with its feature vector; Features is
+// aligned with the feature names of the table it belongs to.
 type MatchupFeatureRow struct {
 	ID     string
 	Season int
 	TeamA  int
 	TeamB  int
 
-	DSeed      float64
-	DElo       float64
-	DWinPct    float64
-	DAvgMargin float64
-	DAvgPF     float64
-	DAvgPA     float64
-	DMasseyOrd float64
+	Features []float64
 
 	Label    float64
 	HasLabel bool
 }
+
+// MasseySummary describes what was loaded from the ordinals file.
+type MasseySummary struct {
+	Rows      int
+	Systems   int
+	Teams     int
+	MinSeason int
+	MaxSeason int
+}
+
+func SummarizeMassey(rows []MasseyRow) MasseySummary {
+	s := MasseySummary{Rows: len(rows)}
+	systems := map[string]struct{}{}
+	teams := map[int]struct{}{}
+	for i, m := range rows {
+		systems[m.System] = struct{}{}
+		teams[m.TeamID] = struct{}{}
+		if i == 0 || m.Season < s.MinSeason {
+			s.MinSeason = m.Season
+		}
+		if i == 0 || m.Season > s.MaxSeason {
+			s.MaxSeason = m.Season
+		}
+	}
+	s.Systems = len(systems)
+	s.Teams = len(teams)
+	return s
+}
+
+func (s MasseySummary) String() string {
+	if s.Rows == 0 {
+		return "0 rows"
+	}
+	return fmt.Sprintf("%d rows, %d systems, %d teams, seasons %d-%d",
+		s.Rows, s.Systems, s.Teams, s.MinSeason, s.MaxSeason)
+}
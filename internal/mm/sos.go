@@ -0,0 +1,208 @@
+package mm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Strength-of-schedule aggregates of a team-season, computed from its
+// regular-season opponents and a rating of every team (Elo in build_features).
+
+type SOSConfig struct {
+	// TopN is the rating rank, within the season and league, that counts as
+	// a top opponent.
+	TopN int
+}
+
+func DefaultSOSConfig() SOSConfig {
+	return SOSConfig{TopN: 50}
+}
+
+type SOS struct {
+	OppRating float64 // average opponent rating, per game
+	OWP       float64 // opponents' win pct, excluding their games against the team
+	OOWP      float64 // opponents' average OWP
+	RPI       float64 // 0.25·WP + 0.5·OWP + 0.25·OOWP
+
+	TopWins  int // wins against top-N opponents
+	TopGames int
+
+	// Quad holds wins and losses by NCAA-style quadrant, Quad[0] being
+	// quadrant 1; see quadrant.
+	Quad [4][2]int
+}
+
+// quadrant buckets a game by the opponent's rating rank and the venue as the
+// NCAA NET quadrants do (0 = quadrant 1). loc is the team's own location.
+func quadrant(rank int, loc string) int {
+	limits := [3]int{50, 100, 200} // neutral
+	switch loc {
+	case "H":
+		limits = [3]int{30, 75, 160}
+	case "A":
+		limits = [3]int{75, 135, 240}
+	}
+	for q, lim := range limits {
+		if rank <= lim {
+			return q
+		}
+	}
+	return 3
+}
+
+// ratingRanks ranks the teams of every season and league by rating, 1 best.
+func ratingRanks(rating map[[2]int]float64) map[[2]int]int {
+	groups := map[seasonKey][]int{}
+	for k := range rating {
+		sk := seasonKey{League: LeagueOfTeam(k[1]), Season: k[0]}
+		groups[sk] = append(groups[sk], k[1])
+	}
+	ranks := make(map[[2]int]int, len(rating))
+	for sk, teams := range groups {
+		sort.Slice(teams, func(i, j int) bool {
+			ri, rj := rating[[2]int{sk.Season, teams[i]}], rating[[2]int{sk.Season, teams[j]}]
+			if ri != rj {
+				return ri > rj
+			}
+			return teams[i] < teams[j]
+		})
+		for i, t := range teams {
+			ranks[[2]int{sk.Season, t}] = i + 1
+		}
+	}
+	return ranks
+}
+
+// BuildSOS computes the schedule strength of every team-season in rows.
+// Opponents without a rating rank below every rated team and count as 0.
+func BuildSOS(rows []RegularSeasonCompactRow, rating map[[2]int]float64, cfg SOSConfig) map[[2]int]SOS {
+	type record struct{ wins, games int }
+	rec := map[[2]int]*record{} // [season, team]
+	vs := map[[3]int]*record{}  // [season, team, opp]
+	for _, g := range rows {
+		for _, k := range [][3]int{{g.Season, g.WTeamID, g.LTeamID}, {g.Season, g.LTeamID, g.WTeamID}} {
+			if rec[[2]int{k[0], k[1]}] == nil {
+				rec[[2]int{k[0], k[1]}] = &record{}
+			}
+			if vs[k] == nil {
+				vs[k] = &record{}
+			}
+			rec[[2]int{k[0], k[1]}].games++
+			vs[k].games++
+		}
+		rec[[2]int{g.Season, g.WTeamID}].wins++
+		vs[[3]int{g.Season, g.WTeamID, g.LTeamID}].wins++
+	}
+
+	// owp is opp's win pct leaving out its games against team.
+	owp := func(season, team, opp int) float64 {
+		r := rec[[2]int{season, opp}]
+		v := vs[[3]int{season, opp, team}]
+		games := r.games - v.games
+		if games <= 0 {
+			return 0
+		}
+		return float64(r.wins-v.wins) / float64(games)
+	}
+
+	type acc struct {
+		sos            SOS
+		oppRating, owp float64
+		opps           [][2]int // one entry per game
+	}
+	ranks := ratingRanks(rating)
+	unranked := len(ranks) + 1
+	accs := map[[2]int]*acc{}
+	for _, g := range rows {
+		ll := "N"
+		switch g.WLoc {
+		case "H":
+			ll = "A"
+		case "A":
+			ll = "H"
+		}
+		for _, side := range []struct {
+			team, opp int
+			won       bool
+			loc       string
+		}{
+			{g.WTeamID, g.LTeamID, true, g.WLoc},
+			{g.LTeamID, g.WTeamID, false, ll},
+		} {
+			k := [2]int{g.Season, side.team}
+			a := accs[k]
+			if a == nil {
+				a = &acc{}
+				accs[k] = a
+			}
+			opp := [2]int{g.Season, side.opp}
+			a.oppRating += rating[opp]
+			a.owp += owp(g.Season, side.team, side.opp)
+			a.opps = append(a.opps, opp)
+
+			rank, ok := ranks[opp]
+			if !ok {
+				rank = unranked
+			}
+			res := 1
+			if side.won {
+				res = 0
+			}
+			a.sos.Quad[quadrant(rank, side.loc)][res]++
+			if rank <= cfg.TopN {
+				a.sos.TopGames++
+				if side.won {
+					a.sos.TopWins++
+				}
+			}
+		}
+	}
+
+	out := make(map[[2]int]SOS, len(accs))
+	for k, a := range accs {
+		n := float64(len(a.opps))
+		a.sos.OppRating = a.oppRating / n
+		a.sos.OWP = a.owp / n
+		out[k] = a.sos
+	}
+	// OOWP averages the opponents' OWP over the team's games.
+	for k, a := range accs {
+		var sum float64
+		for _, o := range a.opps {
+			sum += out[o].OWP
+		}
+		s := out[k]
+		s.OOWP = sum / float64(len(a.opps))
+		r := rec[k]
+		wp := float64(r.wins) / float64(r.games)
+		s.RPI = 0.25*wp + 0.5*s.OWP + 0.25*s.OOWP
+		out[k] = s
+	}
+	return out
+}
+
+// AttachSOS stores the schedule features in each team's Extra values.
+func AttachSOS(agg map[[2]int]*TeamSeasonAgg, sos map[[2]int]SOS) {
+	for k, s := range sos {
+		a, ok := agg[k]
+		if !ok {
+			a = &TeamSeasonAgg{Season: k[0], TeamID: k[1]}
+			agg[k] = a
+		}
+		a.Set("OppRating", s.OppRating)
+		a.Set("OWP", s.OWP)
+		a.Set("OOWP", s.OOWP)
+		a.Set("RPI", s.RPI)
+		a.Set("TopWins", float64(s.TopWins))
+		a.Set("TopGames", float64(s.TopGames))
+		topPct := 0.0
+		if s.TopGames > 0 {
+			topPct = float64(s.TopWins) / float64(s.TopGames)
+		}
+		a.Set("TopWinPct", topPct)
+		for q := range s.Quad {
+			a.Set(fmt.Sprintf("Q%dWins", q+1), float64(s.Quad[q][0]))
+			a.Set(fmt.Sprintf("Q%dLosses", q+1), float64(s.Quad[q][1]))
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package mm
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A data source is either a directory or a .zip archive as downloaded from
+// Kaggle. Members may be plain CSVs or gzip-compressed ("X.csv.gz"); both are
+// found under the plain CSV name.
+
+const gzipExt = ".gz"
+
+func isZipPath(p string) bool {
+	return strings.EqualFold(filepath.Ext(p), ".zip")
+}
+
+// source opens the data source on first use.
+func (l *Loader) source() (fs.FS, error) {
+	if l.fsys != nil {
+		return l.fsys, nil
+	}
+	st, err := os.Stat(l.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() && isZipPath(l.DataDir) {
+		zr, err := zip.OpenReader(l.DataDir)
+		if err != nil {
+			return nil, err
+		}
+		l.fsys, l.closer = zr, zr
+	} else if st.IsDir() {
+		l.fsys = os.DirFS(l.DataDir)
+	} else {
+		return nil, fmt.Errorf("data source %s is neither a directory nor a .zip archive", l.DataDir)
+	}
+	return l.fsys, nil
+}
+
+// Close releases the archive when the source is a .zip file.
+func (l *Loader) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	err := l.closer.Close()
+	l.fsys, l.closer, l.index, l.stamps = nil, nil, nil, nil
+	return err
+}
+
+// buildIndex maps the lower-cased base name of every member to its path,
+// preferring the shallowest match so a flat layout wins over nested copies.
+func (l *Loader) buildIndex() error {
+	if l.index != nil {
+		return nil
+	}
+	fsys, err := l.source()
+	if err != nil {
+		return err
+	}
+	index := make(map[string]string)
+	depth := make(map[string]int)
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), "__MACOSX") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		key := strings.ToLower(d.Name())
+		n := strings.Count(p, "/")
+		if old, ok := depth[key]; !ok || n < old {
+			index[key] = p
+			depth[key] = n
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	l.index = index
+	return nil
+}
+
+// find returns the member path of the first candidate present in the source,
+// matching names case-insensitively and accepting a ".gz" suffix.
+func (l *Loader) find(candidates ...string) (string, error) {
+	if err := l.buildIndex(); err != nil {
+		return "", err
+	}
+	for _, c := range candidates {
+		key := strings.ToLower(c)
+		if p, ok := l.index[key]; ok {
+			return p, nil
+		}
+		if p, ok := l.index[key+gzipExt]; ok {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("file not found in %s: %v: %w", l.DataDir, candidates, ErrNotPresent)
+}
+
+// Files lists the member paths of the source, sorted.
+func (l *Loader) Files() ([]string, error) {
+	if err := l.buildIndex(); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(l.index))
+	for _, p := range l.index {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// displayPath names a member for error messages and cache headers.
+func (l *Loader) displayPath(name string) string {
+	if isZipPath(l.DataDir) {
+		return l.DataDir + "/" + name
+	}
+	return filepath.Join(l.DataDir, filepath.FromSlash(name))
+}
+
+// open returns the decompressed contents of a member.
+func (l *Loader) open(name string) (io.ReadCloser, error) {
+	fsys, err := l.source()
+	if err != nil {
+		return nil, err
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(path.Ext(name), gzipExt) {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s: %w", l.displayPath(name), err)
+	}
+	return &gzipFile{Reader: zr, f: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f fs.File
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Reader.Close()
+	if cerr := g.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
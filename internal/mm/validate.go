@@ -0,0 +1,224 @@
+package mm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Validation categories.
+const (
+	CheckResults    = "results"
+	CheckTourney    = "tourney"
+	CheckSeeds      = "seeds"
+	CheckMassey     = "massey"
+	CheckSubmission = "submission"
+)
+
+type ValidationIssue struct {
+	Category string
+	Severity Severity
+	Message  string
+}
+
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+func (r *ValidationReport) add(category string, sev Severity, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Category: category,
+		Severity: sev,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Count returns the number of issues with the given severity.
+func (r *ValidationReport) Count(sev Severity) int {
+	n := 0
+	for _, is := range r.Issues {
+		if is.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *ValidationReport) HasErrors() bool { return r.Count(SeverityError) > 0 }
+
+// Write prints a per category/severity summary followed by up to maxExamples
+// messages of each group.
+func (r *ValidationReport) Write(w io.Writer, maxExamples int) {
+	if len(r.Issues) == 0 {
+		fmt.Fprintln(w, "Validation: no issues")
+		return
+	}
+	type group struct {
+		category string
+		severity Severity
+	}
+	byGroup := map[group][]string{}
+	var groups []group
+	for _, is := range r.Issues {
+		g := group{is.Category, is.Severity}
+		if _, ok := byGroup[g]; !ok {
+			groups = append(groups, g)
+		}
+		byGroup[g] = append(byGroup[g], is.Message)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].severity != groups[j].severity {
+			return groups[i].severity == SeverityError
+		}
+		return groups[i].category < groups[j].category
+	})
+
+	fmt.Fprintf(w, "Validation: %d errors, %d warnings\n", r.Count(SeverityError), r.Count(SeverityWarning))
+	for _, g := range groups {
+		msgs := byGroup[g]
+		fmt.Fprintf(w, "  [%s] %s: %d\n", g.severity, g.category, len(msgs))
+		for i, m := range msgs {
+			if i == maxExamples {
+				fmt.Fprintf(w, "    ... %d more\n", len(msgs)-maxExamples)
+				break
+			}
+			fmt.Fprintf(w, "    %s\n", m)
+		}
+	}
+}
+
+// ValidationInput holds the loaded tables to check. Empty tables are skipped.
+type ValidationInput struct {
+	Regular       []RegularSeasonCompactRow
+	Tourney       []TourneyCompactRow
+	Seeds         []SeedRow
+	Massey        []MasseyRow
+	SubmissionIDs []string
+}
+
+// Validate checks row-level sanity of every table and the references between
+// them: tournament teams must be seeded, seeded and ranked teams should have
+// played that regular season, and submission IDs must pair known teams of the
+// same league.
+func Validate(in ValidationInput) *ValidationReport {
+	r := &ValidationReport{}
+
+	played := map[[2]int]bool{}
+	regSeasons := map[int]bool{}
+	for i, g := range in.Regular {
+		validateGame(r, CheckResults, fmt.Sprintf("regular row %d", i+1),
+			g.Season, g.DayNum, g.WTeamID, g.WScore, g.LTeamID, g.LScore, g.WLoc, g.NumOT)
+		played[[2]int{g.Season, g.WTeamID}] = true
+		played[[2]int{g.Season, g.LTeamID}] = true
+		regSeasons[g.Season] = true
+	}
+
+	seeded := map[[2]int]bool{}
+	seedSeasons := map[int]bool{}
+	seedCount := map[[2]int]int{}
+	for _, s := range in.Seeds {
+		k := [2]int{s.Season, s.TeamID}
+		seedCount[k]++
+		if seedCount[k] == 2 {
+			r.add(CheckSeeds, SeverityError, "season %d team %d is seeded more than once", s.Season, s.TeamID)
+		}
+		seeded[k] = true
+		seedSeasons[s.Season] = true
+		if s.Seed < 1 || s.Seed > 16 {
+			r.add(CheckSeeds, SeverityError, "season %d team %d has seed %d outside 1-16", s.Season, s.TeamID, s.Seed)
+		}
+		if s.League != "" && LeagueOfTeam(s.TeamID) != s.League {
+			r.add(CheckSeeds, SeverityWarning, "season %d team %d is in the %s seeds file", s.Season, s.TeamID, s.League)
+		}
+		if regSeasons[s.Season] && !played[k] {
+			r.add(CheckSeeds, SeverityWarning, "season %d seeded team %d played no regular-season games", s.Season, s.TeamID)
+		}
+	}
+
+	for i, g := range in.Tourney {
+		validateGame(r, CheckTourney, fmt.Sprintf("tourney row %d", i+1),
+			g.Season, g.DayNum, g.WTeamID, g.WScore, g.LTeamID, g.LScore, g.WLoc, g.NumOT)
+		if !seedSeasons[g.Season] {
+			continue
+		}
+		for _, t := range []int{g.WTeamID, g.LTeamID} {
+			if !seeded[[2]int{g.Season, t}] {
+				r.add(CheckTourney, SeverityError, "season %d day %d: tourney team %d has no seed", g.Season, g.DayNum, t)
+			}
+		}
+	}
+
+	unranked := map[[2]int]bool{}
+	for i, m := range in.Massey {
+		if m.Ordinal < 1 {
+			r.add(CheckMassey, SeverityError, "row %d: %s ordinal %d for team %d is not positive", i+1, m.System, m.Ordinal, m.TeamID)
+		}
+		if m.RankingDay < 0 {
+			r.add(CheckMassey, SeverityError, "row %d: negative RankingDayNum %d", i+1, m.RankingDay)
+		}
+		k := [2]int{m.Season, m.TeamID}
+		if regSeasons[m.Season] && !played[k] && !unranked[k] {
+			unranked[k] = true
+			r.add(CheckMassey, SeverityWarning, "season %d ranked team %d played no regular-season games", m.Season, m.TeamID)
+		}
+	}
+
+	seenID := map[string]bool{}
+	for _, id := range in.SubmissionIDs {
+		if seenID[id] {
+			r.add(CheckSubmission, SeverityError, "duplicate ID %s", id)
+			continue
+		}
+		seenID[id] = true
+		season, a, b, err := ParseMatchupID(id)
+		if err != nil {
+			r.add(CheckSubmission, SeverityError, "%v", err)
+			continue
+		}
+		if a >= b {
+			r.add(CheckSubmission, SeverityError, "ID %s: first team must have the lower TeamID", id)
+		}
+		if LeagueOfTeam(a) != LeagueOfTeam(b) {
+			r.add(CheckSubmission, SeverityError, "ID %s pairs teams from different leagues", id)
+		}
+		if regSeasons[season] {
+			for _, t := range []int{a, b} {
+				if !played[[2]int{season, t}] {
+					r.add(CheckSubmission, SeverityWarning, "ID %s: team %d has no regular-season games in %d", id, t, season)
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+func validateGame(r *ValidationReport, category, where string, season, day, wTeam, wScore, lTeam, lScore int, wLoc string, numOT int) {
+	if wTeam == lTeam {
+		r.add(category, SeverityError, "%s: season %d team %d plays itself", where, season, wTeam)
+	}
+	if wScore <= lScore {
+		r.add(category, SeverityError, "%s: season %d day %d WScore %d <= LScore %d", where, season, day, wScore, lScore)
+	}
+	switch wLoc {
+	case "H", "A", "N":
+	default:
+		r.add(category, SeverityError, "%s: season %d day %d WLoc %q is not H/A/N", where, season, day, wLoc)
+	}
+	if numOT < 0 {
+		r.add(category, SeverityError, "%s: negative NumOT %d", where, numOT)
+	}
+	if day < 0 {
+		r.add(category, SeverityError, "%s: negative DayNum %d", where, day)
+	}
+	if LeagueOfTeam(wTeam) != LeagueOfTeam(lTeam) {
+		r.add(category, SeverityError, "%s: season %d teams %d and %d are from different leagues", where, season, wTeam, lTeam)
+	}
+}
@@ -0,0 +1,129 @@
+package synth
+
+import (
+	"encoding/csv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Chirag314/march-mania-2026-go/internal/mm"
+)
+
+func readCSVFile(t *testing.T, path string) (header []string, rows [][]string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	recs, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	if len(recs) == 0 {
+		t.Fatalf("%s: empty", path)
+	}
+	return recs[0], recs[1:]
+}
+
+func column(t *testing.T, header []string, name string) int {
+	t.Helper()
+	for i, h := range header {
+		if h == name {
+			return i
+		}
+	}
+	t.Fatalf("no %s column in %v", name, header)
+	return -1
+}
+
+// TestPipeline generates a small dataset and runs build_features, train and
+// predict on it as a user would. The go test cache does not see the commands'
+// sources, so run it with -count=1 (make test does) after changing them.
+func TestPipeline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs the commands")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	tmp := t.TempDir()
+	dataDir := filepath.Join(tmp, "data")
+	artDir := filepath.Join(tmp, "artifacts")
+	subDir := filepath.Join(tmp, "submissions")
+	binDir := filepath.Join(tmp, "bin")
+	if err := Generate(dataDir, smallConfig()); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(name string, args ...string) {
+		t.Helper()
+		out, err := exec.Command(filepath.Join(binDir, name), args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", name, err, out)
+		}
+	}
+	build := exec.Command(goBin, "build", "-o", binDir+string(filepath.Separator),
+		"./cmd/build_features", "./cmd/train", "./cmd/predict")
+	build.Dir = filepath.Join("..", "..")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	run("build_features", "--data_dir", dataDir, "--out_dir", artDir, "--strict")
+	run("train", "--art_dir", artDir, "--out_dir", artDir)
+	run("predict", "--art_dir", artDir, "--out_dir", subDir)
+
+	// Every tournament game gives two mirrored training rows.
+	tourneyGames := 0
+	for _, lg := range mm.AllLeagues {
+		_, rows := readCSVFile(t, filepath.Join(dataDir, string(lg)+"NCAATourneyCompactResults.csv"))
+		tourneyGames += len(rows)
+	}
+	trainHeader, trainRows := readCSVFile(t, filepath.Join(artDir, "features_train.csv"))
+	if len(trainRows) != 2*tourneyGames {
+		t.Errorf("features_train.csv has %d rows, want %d", len(trainRows), 2*tourneyGames)
+	}
+	for _, name := range append([]string{"ID", "Season", "TeamA", "TeamB", "Label"}, mm.MatchupFeatureNames()...) {
+		column(t, trainHeader, name)
+	}
+
+	_, sample := readCSVFile(t, filepath.Join(dataDir, "SampleSubmissionStage1.csv"))
+	_, testRows := readCSVFile(t, filepath.Join(artDir, "features_test.csv"))
+	if len(testRows) != len(sample) {
+		t.Errorf("features_test.csv has %d rows, want %d", len(testRows), len(sample))
+	}
+
+	if _, err := mm.LoadModelJSON(filepath.Join(artDir, "model.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	subHeader, subRows := readCSVFile(t, filepath.Join(subDir, "submission.csv"))
+	if strings.Join(subHeader, ",") != "ID,Pred" {
+		t.Errorf("submission header %v", subHeader)
+	}
+	if len(subRows) != len(sample) {
+		t.Fatalf("submission has %d rows, want %d", len(subRows), len(sample))
+	}
+	want := make(map[string]bool, len(sample))
+	for _, r := range sample {
+		want[r[0]] = true
+	}
+	for _, r := range subRows {
+		if !want[r[0]] {
+			t.Errorf("submission ID %s is not in the sample submission", r[0])
+		}
+		p, err := strconv.ParseFloat(r[1], 64)
+		if err != nil {
+			t.Fatalf("%s: %v", r[0], err)
+		}
+		if p < 0.02 || p > 0.98 {
+			t.Errorf("%s: prediction %v outside [0.02, 0.98]", r[0], p)
+		}
+	}
+}
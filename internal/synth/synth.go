@@ -0,0 +1,653 @@
+// Package synth writes a small Kaggle-format March Madness dataset with
+// latent team strengths, so the pipeline can run without Kaggle credentials.
+package synth
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Chirag314/march-mania-2026-go/internal/mm"
+)
+
+type Config struct {
+	Seed        int64
+	FirstSeason int
+	LastSeason  int
+	Leagues     []mm.League
+
+	TeamsPerLeague int
+	Conferences    int
+	// SeedsPerRegion sets the bracket size: 4 regions of this many seeds.
+	// It must be a power of two no larger than 16.
+	SeedsPerRegion int
+	// NonConfGames and ConfGames are the approximate regular-season games per team.
+	NonConfGames int
+	ConfGames    int
+	// SubmissionSeasons is how many of the last seasons get all-pairs
+	// sample submission rows.
+	SubmissionSeasons int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Seed:              1,
+		FirstSeason:       2015,
+		LastSeason:        2024,
+		Leagues:           mm.AllLeagues,
+		TeamsPerLeague:    96,
+		Conferences:       8,
+		SeedsPerRegion:    16,
+		NonConfGames:      11,
+		ConfGames:         18,
+		SubmissionSeasons: 1,
+	}
+}
+
+func (c Config) validate() error {
+	if c.FirstSeason > c.LastSeason {
+		return fmt.Errorf("first season %d after last season %d", c.FirstSeason, c.LastSeason)
+	}
+	n := c.SeedsPerRegion
+	if n < 2 || n > 16 || n&(n-1) != 0 {
+		return fmt.Errorf("seeds per region %d is not a power of two in 2-16", n)
+	}
+	if c.TeamsPerLeague < 4*n {
+		return fmt.Errorf("%d teams per league cannot fill a %d-team bracket", c.TeamsPerLeague, 4*n)
+	}
+	if c.Conferences < 1 || c.Conferences > c.TeamsPerLeague/2 {
+		return fmt.Errorf("bad conference count %d", c.Conferences)
+	}
+	if len(c.Leagues) == 0 {
+		return fmt.Errorf("no leagues")
+	}
+	return nil
+}
+
+const (
+	homeAdv   = 3.5  // points
+	marginSD  = 11.0 // points
+	strengthS = 9.0  // spread of team strengths
+	carryOver = 0.75 // season-to-season strength persistence
+)
+
+var regions = []string{"W", "X", "Y", "Z"}
+
+type team struct {
+	id       int
+	name     string
+	city     int
+	conf     int
+	strength float64
+	pace     float64
+}
+
+type game struct {
+	season, day int
+	a, b        *team
+	loc         string // from a's perspective: H, A or N
+	event       string // secondary tournament name
+}
+
+// table accumulates the rows of one output file.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+type generator struct {
+	cfg    Config
+	rng    *rand.Rand
+	tables map[string]*table
+}
+
+// Generate writes the dataset into dir, creating it if needed. The output
+// depends only on cfg.
+func Generate(dir string, cfg Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	g := &generator{
+		cfg:    cfg,
+		rng:    rand.New(rand.NewSource(cfg.Seed)),
+		tables: make(map[string]*table),
+	}
+	g.writeConferences()
+	g.writeCities()
+	for _, lg := range cfg.Leagues {
+		g.league(lg)
+	}
+	return g.flush(dir)
+}
+
+func (g *generator) add(name string, header []string, row ...string) {
+	t, ok := g.tables[name]
+	if !ok {
+		t = &table{header: header}
+		g.tables[name] = t
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (g *generator) flush(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(g.tables))
+	for n := range g.tables {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := writeCSV(filepath.Join(dir, n), g.tables[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(path string, t *table) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write(t.header)
+	_ = w.WriteAll(t.rows)
+	if err := w.Error(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var confNames = []string{
+	"Atlantic", "Pacific", "Mountain", "Prairie", "Lakes", "Coastal",
+	"Valley", "Southern", "Northern", "Frontier", "Capital", "Heartland",
+}
+
+func confAbbrev(i int) string { return fmt.Sprintf("syn%02d", i+1) }
+
+func (g *generator) writeConferences() {
+	for i := 0; i < g.cfg.Conferences; i++ {
+		name := fmt.Sprintf("Conference %d", i+1)
+		if i < len(confNames) {
+			name = confNames[i] + " Conference"
+		}
+		g.add("Conferences.csv", []string{"ConfAbbrev", "Description"}, confAbbrev(i), name)
+	}
+}
+
+var cities = []struct{ name, state string }{
+	{"Springfield", "IL"}, {"Riverton", "WY"}, {"Fairview", "OR"}, {"Georgetown", "KY"},
+	{"Madison", "WI"}, {"Clinton", "IA"}, {"Franklin", "TN"}, {"Greenville", "SC"},
+	{"Salem", "MA"}, {"Bristol", "CT"}, {"Dover", "DE"}, {"Auburn", "AL"},
+	{"Oxford", "MS"}, {"Arlington", "TX"}, {"Jackson", "MI"}, {"Ashland", "OH"},
+	{"Burlington", "VT"}, {"Clayton", "MO"}, {"Dayton", "OH"}, {"Hudson", "NY"},
+	{"Lexington", "VA"}, {"Milton", "PA"}, {"Newport", "RI"}, {"Marion", "IN"},
+}
+
+func (g *generator) writeCities() {
+	for i, c := range cities {
+		g.add("Cities.csv", []string{"CityID", "City", "State"}, itoa(i+1), c.name, c.state)
+	}
+}
+
+var (
+	places   = []string{"North", "South", "East", "West", "Central", "Coastal", "Upper", "Lower"}
+	suffixes = []string{"State", "Tech", "College", "University", "A&M", "Poly", "Christian", "Baptist", "Valley", "Plains", "Ridge", "Harbor"}
+)
+
+func (g *generator) league(lg mm.League) {
+	cfg := g.cfg
+	base := 1101
+	if lg == mm.LeagueWomen {
+		base = 3101
+	}
+
+	teams := make([]*team, cfg.TeamsPerLeague)
+	for i := range teams {
+		teams[i] = &team{
+			id:       base + i,
+			name:     fmt.Sprintf("%s %s", places[i%len(places)], suffixes[(i/len(places))%len(suffixes)]),
+			city:     i%len(cities) + 1,
+			conf:     i % cfg.Conferences,
+			strength: g.rng.NormFloat64() * strengthS,
+			pace:     68 + g.rng.NormFloat64()*4,
+		}
+		if i >= len(places)*len(suffixes) {
+			teams[i].name += " " + strconv.Itoa(i/(len(places)*len(suffixes))+1)
+		}
+		g.add(lg.FileName("Teams.csv"), []string{"TeamID", "TeamName", "FirstD1Season", "LastD1Season"},
+			itoa(teams[i].id), teams[i].name, itoa(cfg.FirstSeason), itoa(cfg.LastSeason))
+	}
+	// Conference strength makes some leagues visibly deeper than others.
+	confBoost := make([]float64, cfg.Conferences)
+	for i := range confBoost {
+		confBoost[i] = g.rng.NormFloat64() * 3
+	}
+	for _, t := range teams {
+		t.strength += confBoost[t.conf]
+	}
+
+	for season := cfg.FirstSeason; season <= cfg.LastSeason; season++ {
+		if season > cfg.FirstSeason {
+			for _, t := range teams {
+				t.strength = carryOver*t.strength + math.Sqrt(1-carryOver*carryOver)*strengthS*g.rng.NormFloat64() + (1-carryOver)*confBoost[t.conf]
+			}
+		}
+		g.season(lg, season, teams)
+	}
+
+	if cfg.SubmissionSeasons > 0 {
+		for season := cfg.LastSeason - cfg.SubmissionSeasons + 1; season <= cfg.LastSeason; season++ {
+			for i := range teams {
+				for j := i + 1; j < len(teams); j++ {
+					id := fmt.Sprintf("%d_%d_%d", season, teams[i].id, teams[j].id)
+					g.add("SampleSubmissionStage1.csv", []string{"ID", "Pred"}, id, "0.5")
+				}
+			}
+		}
+	}
+}
+
+func (g *generator) season(lg mm.League, season int, teams []*team) {
+	cfg := g.cfg
+
+	dayZero := time.Date(season-1, time.November, 1, 0, 0, 0, 0, time.UTC)
+	for dayZero.Weekday() != time.Monday {
+		dayZero = dayZero.AddDate(0, 0, 1)
+	}
+	g.add(lg.FileName("Seasons.csv"), []string{"Season", "DayZero", "RegionW", "RegionX", "RegionY", "RegionZ"},
+		itoa(season), dayZero.Format("01/02/2006"), "East", "West", "Midwest", "South")
+
+	for _, t := range teams {
+		g.add(lg.FileName("TeamConferences.csv"), []string{"Season", "TeamID", "ConfAbbrev"},
+			itoa(season), itoa(t.id), confAbbrev(t.conf))
+	}
+
+	games := g.schedule(season, teams)
+	wins := make(map[int]int)
+	confWins := make(map[int]int)
+	for _, gm := range games {
+		w, _ := g.play(lg, gm, "RegularSeason")
+		wins[w.id]++
+		if gm.a.conf == gm.b.conf {
+			confWins[w.id]++
+		}
+	}
+	for _, w := range g.confTourneys(lg, season, teams, confWins) {
+		wins[w.id]++
+	}
+
+	g.massey(lg, season, teams)
+
+	// Selection: rank on a noisy mix of strength and record, then seed
+	// serpentine across the regions.
+	n := cfg.SeedsPerRegion
+	type cand struct {
+		t     *team
+		score float64
+	}
+	cands := make([]cand, len(teams))
+	for i, t := range teams {
+		cands[i] = cand{t, t.strength + 0.5*float64(wins[t.id]) + g.rng.NormFloat64()*2}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].score > cands[j].score })
+
+	bySeed := make(map[string]*team)
+	for i := 0; i < 4*n; i++ {
+		seedNum := i/4 + 1
+		r := i % 4
+		if seedNum%2 == 0 {
+			r = 3 - r
+		}
+		code := fmt.Sprintf("%s%02d", regions[r], seedNum)
+		bySeed[code] = cands[i].t
+		g.add(lg.FileName("NCAATourneySeeds.csv"), []string{"Season", "Seed", "TeamID"},
+			itoa(season), code, itoa(cands[i].t.id))
+	}
+
+	g.tourney(lg, season, bySeed)
+
+	var nit []*team
+	for i := 4 * n; i < len(cands) && len(nit) < 16; i++ {
+		nit = append(nit, cands[i].t)
+	}
+	if len(nit) == 16 {
+		g.secondary(lg, season, "NIT", nit)
+	}
+}
+
+// confTourneys plays a four-team conference tournament (semifinals on day
+// 130, final on day 132) between the best conference records. The games are
+// regular-season results, also listed in ConferenceTourneyGames. It returns
+// the winners of every game.
+func (g *generator) confTourneys(lg mm.League, season int, teams []*team, confWins map[int]int) []*team {
+	var winners []*team
+	for c := 0; c < g.cfg.Conferences; c++ {
+		var members []*team
+		for _, t := range teams {
+			if t.conf == c {
+				members = append(members, t)
+			}
+		}
+		if len(members) < 4 {
+			continue
+		}
+		sort.SliceStable(members, func(i, j int) bool { return confWins[members[i].id] > confWins[members[j].id] })
+
+		play := func(day int, a, b *team) *team {
+			w, l := g.play(lg, game{season: season, day: day, a: a, b: b, loc: "N"}, "RegularSeason")
+			g.add(lg.FileName("ConferenceTourneyGames.csv"), []string{"Season", "ConfAbbrev", "DayNum", "WTeamID", "LTeamID"},
+				itoa(season), confAbbrev(c), itoa(day), itoa(w.id), itoa(l.id))
+			winners = append(winners, w)
+			return w
+		}
+		f1 := play(130, members[0], members[3])
+		f2 := play(130, members[1], members[2])
+		play(132, f1, f2)
+	}
+	return winners
+}
+
+// secondaryDays are the DayNums of the four secondary tournament rounds.
+var secondaryDays = []int{135, 138, 142, 152}
+
+// secondary plays a 16-team single elimination tournament; the better team
+// hosts the first two rounds and the last two are on a neutral court.
+func (g *generator) secondary(lg mm.League, season int, name string, field []*team) {
+	for round := 0; len(field) > 1; round++ {
+		var next []*team
+		half := len(field) / 2
+		for i := 0; i < half; i++ {
+			loc := "H"
+			if round >= 2 {
+				loc = "N"
+			}
+			gm := game{season: season, day: secondaryDays[round], a: field[i], b: field[len(field)-1-i], loc: loc, event: name}
+			w, _ := g.play(lg, gm, "SecondaryTourney")
+			next = append(next, w)
+		}
+		field = next
+	}
+}
+
+// schedule builds the non-conference phase (days 11-60) and the conference
+// phase (days 64-128) of a season.
+func (g *generator) schedule(season int, teams []*team) []game {
+	var games []game
+	pairUp := func(day int, pool []*team) {
+		idx := g.rng.Perm(len(pool))
+		for i := 0; i+1 < len(idx); i += 2 {
+			a, b := pool[idx[i]], pool[idx[i+1]]
+			loc := "H"
+			switch u := g.rng.Float64(); {
+			case u < 0.1:
+				loc = "N"
+			case u < 0.55:
+				loc = "A"
+			}
+			games = append(games, game{season: season, day: day, a: a, b: b, loc: loc})
+		}
+	}
+
+	for k := 0; k < g.cfg.NonConfGames; k++ {
+		day := 11 + k*49/max(g.cfg.NonConfGames, 1)
+		pairUp(day, teams)
+	}
+
+	byConf := make([][]*team, g.cfg.Conferences)
+	for _, t := range teams {
+		byConf[t.conf] = append(byConf[t.conf], t)
+	}
+	for k := 0; k < g.cfg.ConfGames; k++ {
+		day := 64 + k*64/max(g.cfg.ConfGames, 1)
+		for _, pool := range byConf {
+			pairUp(day, pool)
+		}
+	}
+
+	sort.SliceStable(games, func(i, j int) bool { return games[i].day < games[j].day })
+	return games
+}
+
+// play simulates one game, writes it to the results files of the given kind
+// ("RegularSeason", "NCAATourney" or "SecondaryTourney") and returns the
+// winner and loser. Secondary tournaments only have compact results.
+func (g *generator) play(lg mm.League, gm game, kind string) (winner, loser *team) {
+	adv := 0.0
+	switch gm.loc {
+	case "H":
+		adv = homeAdv
+	case "A":
+		adv = -homeAdv
+	}
+	margin := gm.a.strength - gm.b.strength + adv + g.rng.NormFloat64()*marginSD
+	pace := (gm.a.pace+gm.b.pace)/2 + g.rng.NormFloat64()*3
+	total := 2 * pace * 1.03
+
+	sa := int(math.Round((total + margin) / 2))
+	sb := int(math.Round((total - margin) / 2))
+	numOT := 0
+	for sa == sb {
+		numOT++
+		sa += 4 + g.rng.Intn(9)
+		sb += 4 + g.rng.Intn(9)
+	}
+
+	winner, loser = gm.a, gm.b
+	ws, ls, wloc := sa, sb, gm.loc
+	if sb > sa {
+		winner, loser = gm.b, gm.a
+		ws, ls = sb, sa
+		wloc = flipLoc(gm.loc)
+	}
+
+	compact := []string{
+		itoa(gm.season), itoa(gm.day), itoa(winner.id), itoa(ws), itoa(loser.id), itoa(ls), wloc, itoa(numOT),
+	}
+	if kind == "SecondaryTourney" {
+		g.add(lg.FileName(kind+"CompactResults.csv"), append(append([]string{}, compactHeader...), "SecondaryTourney"),
+			append(compact, gm.event)...)
+	} else {
+		g.add(lg.FileName(kind+"CompactResults.csv"), compactHeader, compact...)
+
+		pos := pace * (1 + 0.12*float64(numOT))
+		wb := g.boxScore(ws, pos)
+		lb := g.boxScore(ls, pos)
+		// Rebounds: one team's defensive boards are the other's missed shots it
+		// did not grab back.
+		wb.DR = max(0, lb.FGA-lb.FGM-lb.OR+g.rng.Intn(5)-2)
+		lb.DR = max(0, wb.FGA-wb.FGM-wb.OR+g.rng.Intn(5)-2)
+		detailed := append(append(append([]string{}, compact...), boxFields(wb)...), boxFields(lb)...)
+		g.add(lg.FileName(kind+"DetailedResults.csv"), detailedHeader, detailed...)
+	}
+
+	city := gm.a.city
+	switch gm.loc {
+	case "A":
+		city = gm.b.city
+	case "N":
+		city = g.rng.Intn(len(cities)) + 1
+	}
+	crType := "Regular"
+	switch kind {
+	case "NCAATourney":
+		crType = "NCAA"
+	case "SecondaryTourney":
+		crType = "Secondary"
+	}
+	g.add(lg.FileName("GameCities.csv"), []string{"Season", "DayNum", "WTeamID", "LTeamID", "CRType", "CityID"},
+		itoa(gm.season), itoa(gm.day), itoa(winner.id), itoa(loser.id), crType, itoa(city))
+	return winner, loser
+}
+
+func flipLoc(loc string) string {
+	switch loc {
+	case "H":
+		return "A"
+	case "A":
+		return "H"
+	}
+	return loc
+}
+
+// boxScore draws a stat line that adds up to exactly pts points.
+func (g *generator) boxScore(pts int, pos float64) mm.BoxScore {
+	var b mm.BoxScore
+	b.FTA = max(0, int(math.Round(pos*0.28+g.rng.NormFloat64()*4)))
+	b.FTM = int(math.Round(float64(b.FTA) * (0.68 + g.rng.NormFloat64()*0.06)))
+	b.FTM = min(max(b.FTM, 0), b.FTA)
+	b.FGM3 = max(0, int(math.Round(float64(pts)*0.11+g.rng.NormFloat64()*2)))
+
+	for 3*b.FGM3+b.FTM > pts {
+		if b.FGM3 > 0 {
+			b.FGM3--
+		} else {
+			b.FTM--
+		}
+	}
+	rest := pts - 3*b.FGM3 - b.FTM
+	if rest%2 == 1 {
+		if b.FTM < b.FTA || b.FTM == 0 {
+			b.FTM++
+			b.FTA = max(b.FTA, b.FTM)
+			rest--
+		} else {
+			b.FTM--
+			rest++
+		}
+	}
+	fgm2 := rest / 2
+
+	b.FGA3 = b.FGM3 + max(0, int(math.Round(float64(b.FGM3)*1.9+g.rng.NormFloat64()*2)))
+	b.FGM = fgm2 + b.FGM3
+	b.FGA = b.FGA3 + fgm2 + max(0, int(math.Round(float64(fgm2)*1.05+g.rng.NormFloat64()*3)))
+	b.OR = max(0, int(math.Round(float64(b.FGA-b.FGM)*0.3+g.rng.NormFloat64()*2)))
+	b.TO = max(0, int(math.Round(pos*0.18+g.rng.NormFloat64()*3)))
+	b.Ast = max(0, int(math.Round(float64(b.FGM)*0.55+g.rng.NormFloat64()*2)))
+	b.Stl = max(0, int(math.Round(6+g.rng.NormFloat64()*2)))
+	b.Blk = max(0, int(math.Round(3+g.rng.NormFloat64()*1.5)))
+	b.PF = max(0, int(math.Round(18+g.rng.NormFloat64()*3)))
+	return b
+}
+
+var compactHeader = []string{"Season", "DayNum", "WTeamID", "WScore", "LTeamID", "LScore", "WLoc", "NumOT"}
+
+var boxStats = []string{"FGM", "FGA", "FGM3", "FGA3", "FTM", "FTA", "OR", "DR", "Ast", "TO", "Stl", "Blk", "PF"}
+
+var detailedHeader = func() []string {
+	h := append([]string{}, compactHeader...)
+	for _, p := range []string{"W", "L"} {
+		for _, s := range boxStats {
+			h = append(h, p+s)
+		}
+	}
+	return h
+}()
+
+func boxFields(b mm.BoxScore) []string {
+	return []string{
+		itoa(b.FGM), itoa(b.FGA), itoa(b.FGM3), itoa(b.FGA3), itoa(b.FTM), itoa(b.FTA),
+		itoa(b.OR), itoa(b.DR), itoa(b.Ast), itoa(b.TO), itoa(b.Stl), itoa(b.Blk), itoa(b.PF),
+	}
+}
+
+var masseyDays = []int{30, 58, 86, 114, 133}
+
+// massey publishes ordinal rankings from two noisy "systems" whose error
+// shrinks as the season goes on. Like Kaggle, only the men's league has them.
+func (g *generator) massey(lg mm.League, season int, teams []*team) {
+	if lg != mm.LeagueMen {
+		return
+	}
+	for _, sys := range []struct {
+		name  string
+		noise float64
+	}{{"SYN", 4}, {"ALT", 6}} {
+		for _, day := range masseyDays {
+			noise := sys.noise * (1.5 - float64(day)/133)
+			type scored struct {
+				t *team
+				v float64
+			}
+			sc := make([]scored, len(teams))
+			for i, t := range teams {
+				sc[i] = scored{t, t.strength + g.rng.NormFloat64()*noise}
+			}
+			sort.SliceStable(sc, func(i, j int) bool { return sc[i].v > sc[j].v })
+			for rank, s := range sc {
+				g.add("MMasseyOrdinals.csv", []string{"Season", "RankingDayNum", "SystemName", "TeamID", "OrdinalRank"},
+					itoa(season), itoa(day), sys.name, itoa(s.t.id), itoa(rank+1))
+			}
+		}
+	}
+}
+
+// roundDays are the DayNums of tournament rounds 1-6.
+var roundDays = [][]int{{136, 137}, {138, 139}, {143, 144}, {145, 146}, {152}, {154}}
+
+// tourney writes the bracket slots and simulates the tournament on neutral
+// courts. Slot names follow Kaggle: R1W1 pits W01 against the lowest seed,
+// later rounds feed from earlier slots, R<k>WX/R<k>YZ are the semifinals and
+// R<k>CH the final.
+func (g *generator) tourney(lg mm.League, season int, bySeed map[string]*team) {
+	n := g.cfg.SeedsPerRegion
+	regionRounds := 0
+	for m := n; m > 1; m /= 2 {
+		regionRounds++
+	}
+
+	type slot struct {
+		name, strong, weak string
+		round              int
+	}
+	var slots []slot
+	for r := 1; r <= regionRounds; r++ {
+		games := n >> r
+		for _, reg := range regions {
+			for k := 1; k <= games; k++ {
+				var strong, weak string
+				if r == 1 {
+					strong = fmt.Sprintf("%s%02d", reg, k)
+					weak = fmt.Sprintf("%s%02d", reg, n+1-k)
+				} else {
+					strong = fmt.Sprintf("R%d%s%d", r-1, reg, k)
+					weak = fmt.Sprintf("R%d%s%d", r-1, reg, 2*games+1-k)
+				}
+				slots = append(slots, slot{fmt.Sprintf("R%d%s%d", r, reg, k), strong, weak, r})
+			}
+		}
+	}
+	semi := regionRounds + 1
+	slots = append(slots,
+		slot{fmt.Sprintf("R%dWX", semi), fmt.Sprintf("R%dW1", regionRounds), fmt.Sprintf("R%dX1", regionRounds), semi},
+		slot{fmt.Sprintf("R%dYZ", semi), fmt.Sprintf("R%dY1", regionRounds), fmt.Sprintf("R%dZ1", regionRounds), semi},
+		slot{fmt.Sprintf("R%dCH", semi+1), fmt.Sprintf("R%dWX", semi), fmt.Sprintf("R%dYZ", semi), semi + 1},
+	)
+
+	// Round numbers are aligned so the final is always round 6.
+	offset := 6 - (semi + 1)
+	winners := make(map[string]*team, len(bySeed)+len(slots))
+	for code, t := range bySeed {
+		winners[code] = t
+	}
+	for i, s := range slots {
+		g.add(lg.FileName("NCAATourneySlots.csv"), []string{"Season", "Slot", "StrongSeed", "WeakSeed"},
+			itoa(season), s.name, s.strong, s.weak)
+
+		days := roundDays[s.round+offset-1]
+		gm := game{season: season, day: days[i%len(days)], a: winners[s.strong], b: winners[s.weak], loc: "N"}
+		w, _ := g.play(lg, gm, "NCAATourney")
+		winners[s.name] = w
+	}
+}
+
+func itoa(x int) string { return strconv.Itoa(x) }
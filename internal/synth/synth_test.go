@@ -0,0 +1,90 @@
+package synth
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// smallConfig is a few seasons of a 32-team league with a 16-team bracket.
+func smallConfig() Config {
+	cfg := DefaultConfig()
+	cfg.FirstSeason = 2018
+	cfg.LastSeason = 2021
+	cfg.TeamsPerLeague = 32
+	cfg.Conferences = 4
+	cfg.SeedsPerRegion = 4
+	return cfg
+}
+
+func readDir(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[e.Name()] = b
+	}
+	return out
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	cfg := smallConfig()
+	a, b := t.TempDir(), t.TempDir()
+	if err := Generate(a, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(b, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	filesA, filesB := readDir(t, a), readDir(t, b)
+	if len(filesA) == 0 {
+		t.Fatal("no files generated")
+	}
+	if len(filesA) != len(filesB) {
+		t.Fatalf("file count %d vs %d", len(filesA), len(filesB))
+	}
+	for name, want := range filesA {
+		if got, ok := filesB[name]; !ok {
+			t.Errorf("%s missing from second run", name)
+		} else if !bytes.Equal(got, want) {
+			t.Errorf("%s differs between runs with seed %d", name, cfg.Seed)
+		}
+	}
+
+	cfg.Seed++
+	c := t.TempDir()
+	if err := Generate(c, cfg); err != nil {
+		t.Fatal(err)
+	}
+	const name = "MRegularSeasonCompactResults.csv"
+	if bytes.Equal(readDir(t, c)[name], filesA[name]) {
+		t.Errorf("%s is identical for seeds %d and %d", name, cfg.Seed-1, cfg.Seed)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		change func(*Config)
+	}{
+		{"seasons reversed", func(c *Config) { c.FirstSeason = c.LastSeason + 1 }},
+		{"seeds not power of two", func(c *Config) { c.SeedsPerRegion = 6 }},
+		{"bracket too big", func(c *Config) { c.TeamsPerLeague = 15 }},
+		{"no leagues", func(c *Config) { c.Leagues = nil }},
+	} {
+		cfg := smallConfig()
+		tc.change(&cfg)
+		if err := Generate(t.TempDir(), cfg); err == nil {
+			t.Errorf("%s: Generate succeeded", tc.name)
+		}
+	}
+}
@@ -19,5 +19,5 @@ else
   exit 1
 fi
 
+# The readers stream members straight from the archive, so it is kept as is.
 kaggle competitions download -c "$COMP" -p "$OUT" --force
-unzip -o "$OUT"/*.zip -d "$OUT"